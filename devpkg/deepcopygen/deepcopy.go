@@ -110,6 +110,7 @@ func(in *@Type) DeepCopyInto(out *@Type) {
 `, snippet.Args{
 			"Type": snippet.ID(named.Obj()),
 			"fieldsCopies": &helper.StructFieldsCopy{
+				Context:          c,
 				Pkg:              named.Obj().Pkg(),
 				Struct:           x,
 				DeepCopyIntoName: "DeepCopyInto",
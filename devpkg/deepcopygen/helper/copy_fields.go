@@ -5,6 +5,7 @@ import (
 	"go/types"
 	"iter"
 
+	"github.com/octohelm/gengo/pkg/gengo"
 	"github.com/octohelm/gengo/pkg/gengo/snippet"
 )
 
@@ -16,6 +17,9 @@ type FieldContext struct {
 }
 
 type StructFieldsCopy struct {
+	// Context when set, fields disabled by field-level tags will be skipped,
+	// like `+gengo:deepcopy=false` or `+gengo:deepcopy:skip`
+	Context          gengo.Context
 	Pkg              *types.Package
 	Struct           *types.Struct
 	DeepCopyName     string
@@ -46,6 +50,10 @@ func (sfc *StructFieldsCopy) Frag(ctx context.Context) iter.Seq[string] {
 				continue
 			}
 
+			if sfc.Context != nil && !sfc.Context.IsFieldEnabled(f) {
+				continue
+			}
+
 			for code := range snippet.Fragments(ctx, sfc.createFieldSnippet(f)) {
 				if !yield(code) {
 					return
@@ -120,6 +120,7 @@ func (in *@Type) DeepCopyIntoAs(out *@OriginType)  {
 		"OriginType": snippet.ID(ps.Origin),
 
 		"fieldsCopies": &helper.StructFieldsCopy{
+			Context:          c,
 			Pkg:              named.Obj().Pkg(),
 			DeepCopyIntoName: "DeepCopyIntoAs",
 			DeepCopyName:     "DeepCopyAs",
@@ -152,6 +153,10 @@ func (in *@Type) DeepCopyIntoAs(out *@OriginType)  {
 						continue
 					}
 
+					if !c.IsFieldEnabled(f) {
+						continue
+					}
+
 					_, fieldDoc := c.Doc(f)
 
 					if replaceTo, ok := ps.Replace[fieldName]; ok {
@@ -18,8 +18,6 @@ func init() {
 
 type runtimedocGen struct {
 	processed map[*types.Named]bool
-
-	helperWritten bool
 }
 
 func (*runtimedocGen) Name() string {
@@ -37,29 +35,18 @@ func (g *runtimedocGen) GenerateType(c gengo.Context, named *types.Named) error
 		return gengo.ErrSkip
 	}
 
-	if g.processed == nil {
-		g.processed = map[*types.Named]bool{}
-	}
-
-	if err := g.generateType(c, named); err != nil {
-		return err
-	}
-
-	if !c.IsZero() {
-		c.Defer(func(c gengo.Context) error {
-			g.createHelperOnce(c)
-			return nil
-		})
-	}
+	return g.generateType(c, named)
+}
 
+func (g *runtimedocGen) BeginPackage(c gengo.Context) error {
+	g.processed = map[*types.Named]bool{}
 	return nil
 }
 
-func (g *runtimedocGen) createHelperOnce(c gengo.Context) {
-	if g.helperWritten {
-		return
+func (g *runtimedocGen) EndPackage(c gengo.Context) error {
+	if c.IsZero() {
+		return nil
 	}
-	g.helperWritten = true
 
 	c.Render(snippet.Block(`
 // nolint:deadcode,unused
@@ -80,6 +67,8 @@ func runtimeDoc(v any, prefix string, names ...string) ([]string, bool) {
 	return nil, false
 }
 `))
+
+	return nil
 }
 
 func hasExposeField(t *types.Struct) bool {
@@ -147,19 +136,16 @@ func(v *@Type) RuntimeDoc(names ...string) ([]string, bool) {
 		@embeds
 		return nil, false
 	}
-	return @doc, true
+	return []string{
+		@range(doc)@.,
+		@end
+	}, true
 }
 
 `, snippet.Args{
 			"Type":           snippet.ID(named.Obj()),
 			"externalEmbeds": snippet.Snippets(slices.Values(embeds)),
-			"doc": snippet.Sprintf("[]string{\n%T\n}", snippet.Snippets(func(yield func(snippet.Snippet) bool) {
-				for _, line := range finalDoc {
-					if !yield(snippet.Sprintf("%v,\n", line)) {
-						return
-					}
-				}
-			})),
+			"doc":            snippet.Snippets(slices.Values(finalDoc)),
 			"cases": snippet.Snippets(func(yield func(snippet.Snippet) bool) {
 				for i := 0; i < x.NumFields(); i++ {
 					f := x.Field(i)
@@ -172,6 +158,10 @@ func(v *@Type) RuntimeDoc(names ...string) ([]string, bool) {
 						continue
 					}
 
+					if !c.IsFieldEnabled(f) {
+						continue
+					}
+
 					_, fieldDoc := c.Doc(f)
 
 					if _, ok := f.Type().(*types.Struct); ok {
@@ -208,6 +198,10 @@ case @fieldName:
 					f := x.Field(i)
 
 					if f.Embedded() {
+						if !c.IsFieldEnabled(f) {
+							continue
+						}
+
 						if s, ok := f.Type().Underlying().(*types.Struct); ok {
 							if !hasExposeField(s) {
 								continue
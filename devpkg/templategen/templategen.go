@@ -0,0 +1,144 @@
+package templategen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/types"
+	"iter"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	"github.com/octohelm/gengo/pkg/gengo/model"
+	"github.com/octohelm/gengo/pkg/gengo/snippet"
+)
+
+// DefaultPattern of template files in project
+const DefaultPattern = ".gengo/templates/*.tmpl"
+
+// Load creates generators from text/template files matched by pattern.
+// The name of each template file without ext will be the generator name,
+// and each type enabled with `+gengo:<name>` will be rendered with model.Type as data.
+//
+// Helpers could be used in template:
+//
+//	id       render type reference (type ref string, types.Type, *types.TypeName), import will be tracked.
+//	value    render go value as literal.
+//	structTag get value of struct tag by key.
+//	hasTag   check if tags contains key.
+//	upperCamelCase, lowerCamelCase, upperSnakeCase, lowerSnakeCase.
+func Load(pattern string) ([]gengo.Generator, error) {
+	filenames, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	generators := make([]gengo.Generator, 0, len(filenames))
+
+	for _, filename := range filenames {
+		name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+
+		t, err := template.New(filepath.Base(filename)).Funcs(funcs(context.Background())).ParseFiles(filename)
+		if err != nil {
+			return nil, fmt.Errorf("parse template %s failed: %w", filename, err)
+		}
+
+		generators = append(generators, &templateGen{name: name, tmpl: t})
+	}
+
+	return generators, nil
+}
+
+// Register loads generators from template files matched by pattern, and registers them by gengo.Register,
+// then they could be picked by gengo.GetRegisteredGenerators same as generators registered in init.
+func Register(pattern string) error {
+	generators, err := Load(pattern)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range generators {
+		gengo.Register(g)
+	}
+
+	return nil
+}
+
+type templateGen struct {
+	name string
+	tmpl *template.Template
+}
+
+func (g *templateGen) Name() string {
+	return g.name
+}
+
+func (g *templateGen) New(c gengo.Context) gengo.Generator {
+	return g
+}
+
+func (g *templateGen) GenerateType(c gengo.Context, named *types.Named) error {
+	t, err := g.tmpl.Clone()
+	if err != nil {
+		return err
+	}
+
+	data := model.TypeOf(c, named)
+
+	var renderErr error
+
+	c.Render(snippet.Func(func(ctx context.Context) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			b := bytes.NewBuffer(nil)
+
+			// helpers should render with ctx to use the raw namer
+			if err := t.Funcs(funcs(ctx)).Execute(b, data); err != nil {
+				renderErr = err
+				return
+			}
+
+			if !yield(b.String()) {
+				return
+			}
+		}
+	}))
+
+	if renderErr != nil {
+		return fmt.Errorf("render template %s for %s failed: %w", t.Name(), named.Obj().Name(), renderErr)
+	}
+
+	return nil
+}
+
+func funcs(ctx context.Context) template.FuncMap {
+	render := func(s snippet.Snippet) string {
+		b := &strings.Builder{}
+		for code := range snippet.Fragments(ctx, s) {
+			b.WriteString(code)
+		}
+		return b.String()
+	}
+
+	return template.FuncMap{
+		"id": func(v any) string {
+			return render(snippet.ID(v))
+		},
+		"value": func(v any) string {
+			return render(snippet.Value(v))
+		},
+		"structTag": func(tag string, key string) string {
+			return reflect.StructTag(tag).Get(key)
+		},
+		"hasTag": func(tags map[string][]string, key string) bool {
+			_, ok := tags[key]
+			return ok
+		},
+		"upperCamelCase": gengo.UpperCamelCase,
+		"lowerCamelCase": gengo.LowerCamelCase,
+		"upperSnakeCase": gengo.UpperSnakeCase,
+		"lowerSnakeCase": gengo.LowerSnakeCase,
+	}
+}
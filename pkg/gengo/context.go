@@ -4,12 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"github.com/octohelm/gengo/pkg/sumfile"
+	"go/ast"
 	"go/token"
 	"go/types"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -26,6 +29,12 @@ type Executor interface {
 }
 
 func NewContext(args *GeneratorArgs) (Executor, error) {
+	for _, filename := range args.Plugins {
+		if err := LoadPlugin(filename); err != nil {
+			return nil, err
+		}
+	}
+
 	u, err := gengotypes.Load(args.Entrypoint)
 	if err != nil {
 		return nil, err
@@ -46,12 +55,22 @@ type Context interface {
 
 	LocateInPackage(pos token.Pos) gengotypes.Package
 	Package(importPath string) gengotypes.Package
+	// Implementations named types of local packages which implement the interface, see gengotypes.Universe.Implementations
+	Implementations(iface gengotypes.TypeName) ([]gengotypes.Implementation, error)
 	Doc(typ types.Object) (Tags, []string)
+	// IsFieldEnabled check if struct field not disabled for current generator by its tags,
+	// which are merged from package, type and field levels
+	IsFieldEnabled(f *types.Var) bool
 
 	Render(snippet snippet.Snippet)
+	// RenderT compiles template with args up front,
+	// invalid template, missing args and render errors will be returned as errors of generating,
+	// unused args will be logged as warning only
 	RenderT(template string, args ...snippet.TArg)
 }
 
+var _ ErrReporter = &gengoCtx{}
+
 type gengoCtx struct {
 	args     *GeneratorArgs
 	universe *gengotypes.Universe
@@ -59,11 +78,15 @@ type gengoCtx struct {
 	pkgTags map[string][]string
 	pkg     gengotypes.Package
 	genfile *genfile
+	gen     Generator
 
 	ignore  bool
 	sumFile *sumfile.File
+	// universeFilenames written by UniverseGenerator for each package path
+	universeFilenames map[string][]string
 
 	defers []func(ctx Context) error
+	errs   []error
 
 	l logr.Logger
 }
@@ -81,7 +104,30 @@ func (c *gengoCtx) Logger() logr.Logger {
 }
 
 func (c *gengoCtx) RenderT(template string, args ...snippet.TArg) {
-	c.genfile.Render(snippet.T(template, args...))
+	t, err := snippet.Compile(template, args...)
+	if err != nil {
+		tErr := &snippet.TemplateError{}
+		if !errors.As(err, &tErr) || len(tErr.Missing) > 0 || tErr.Msg != "" {
+			c.errs = append(c.errs, err)
+			return
+		}
+
+		// unused args are not fatal
+		if c.l != nil {
+			c.l.Warn(err)
+		}
+
+		t = snippet.T(template, args...)
+	}
+	c.genfile.Render(t)
+}
+
+// Err returns errors reported during rendering, which will fail the generating finally
+func (c *gengoCtx) Err() error {
+	if c.genfile == nil {
+		return errors.Join(c.errs...)
+	}
+	return errors.Join(append(c.errs, c.genfile.Err())...)
 }
 
 func (c *gengoCtx) Render(snippet snippet.Snippet) {
@@ -105,12 +151,33 @@ func (c *gengoCtx) Execute(ctx corecontext.Context, generators ...Generator) err
 		}
 	}
 
+	pkgGenerators := make([]Generator, 0, len(generators))
+	universeGenerators := make([]Generator, 0)
+
+	for _, g := range generators {
+		if _, ok := g.(UniverseGenerator); ok {
+			universeGenerators = append(universeGenerators, g)
+			continue
+		}
+		pkgGenerators = append(pkgGenerators, g)
+	}
+
+	if len(universeGenerators) > 0 {
+		c.universeFilenames = c.universeFilenamesOf(universeGenerators...)
+	}
+
 	for pkgPath, direct := range c.universe.LocalPkgPaths() {
 		if !c.args.All && !direct {
 			continue
 		}
 
-		if err := c.pkgExecute(logr.LoggerInjectContext(ctx, c.l), pkgPath, generators...); err != nil {
+		if err := c.pkgExecute(logr.LoggerInjectContext(ctx, c.l), pkgPath, pkgGenerators...); err != nil {
+			return err
+		}
+	}
+
+	if len(universeGenerators) > 0 {
+		if err := c.universeExecute(logr.LoggerInjectContext(ctx, c.l), universeGenerators...); err != nil {
 			return err
 		}
 	}
@@ -168,7 +235,7 @@ func (c *gengoCtx) pkgExecute(pctx corecontext.Context, pkg string, generators .
 		universe: c.universe,
 		args:     c.args,
 		pkg:      p,
-		pkgTags:  map[string][]string{},
+		pkgTags:  pkgTagsOf(p),
 	}
 
 	for _, f := range p.Files() {
@@ -177,13 +244,11 @@ func (c *gengoCtx) pkgExecute(pctx corecontext.Context, pkg string, generators .
 		if strings.HasPrefix(filename, c.args.OutputFileBaseName+".") {
 			generatedFiles[filename] = fileFullname
 		}
+	}
 
-		if f.Doc != nil && len(f.Doc.List) > 0 {
-			tags, _ := gengotypes.ExtractCommentTags(strings.Split(f.Doc.Text(), "\n"))
-			for k := range tags {
-				pkgCtx.pkgTags[k] = tags[k]
-			}
-		}
+	// written by UniverseGenerator, not stale
+	for _, filename := range c.universeFilenames[pkg] {
+		delete(generatedFiles, filename)
 	}
 
 	gfs := sync.Map{}
@@ -194,7 +259,7 @@ func (c *gengoCtx) pkgExecute(pctx corecontext.Context, pkg string, generators .
 			universe: pkgCtx.universe,
 			pkg:      pkgCtx.pkg,
 			pkgTags:  pkgCtx.pkgTags,
-			genfile:  newGenfile(gen.Name()),
+			genfile:  newGenfile(gen.Name(), pkgCtx.args.ImportNaming),
 		}
 
 		if err := pkgCtxForGen.genfile.InitWith(pkgCtxForGen); err != nil {
@@ -203,6 +268,9 @@ func (c *gengoCtx) pkgExecute(pctx corecontext.Context, pkg string, generators .
 
 		g := pkgCtxForGen.New(gen)
 
+		pkgCtxForGen.gen = g
+		pkgCtxForGen.genfile.configure(c.args, g)
+
 		pkgCtxForGen.l = l.WithValues("gengo", g.Name())
 
 		if err := pkgCtxForGen.doGenerate(ctx, g); err != nil {
@@ -215,6 +283,10 @@ func (c *gengoCtx) pkgExecute(pctx corecontext.Context, pkg string, generators .
 			}
 		}
 
+		if err := pkgCtxForGen.Err(); err != nil {
+			return fmt.Errorf("`%s` render failed for %s: %w", g.Name(), pkgCtx.pkg.Pkg().Path(), err)
+		}
+
 		if !pkgCtxForGen.IsZero() {
 			gfs.Store(g.Name(), pkgCtxForGen.genfile)
 		}
@@ -235,12 +307,30 @@ func (c *gengoCtx) pkgExecute(pctx corecontext.Context, pkg string, generators .
 			if err := os.RemoveAll(fullFilename); err != nil {
 				return err
 			}
+			if err := removeSourceMap(fullFilename); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+func pkgTagsOf(p gengotypes.Package) map[string][]string {
+	pkgTags := map[string][]string{}
+
+	for _, f := range p.Files() {
+		if f.Doc != nil && len(f.Doc.List) > 0 {
+			tags, _ := gengotypes.ExtractCommentTags(strings.Split(f.Doc.Text(), "\n"))
+			for k := range tags {
+				pkgTags[k] = tags[k]
+			}
+		}
+	}
+
+	return pkgTags
+}
+
 func (c *gengoCtx) Package(importPath string) gengotypes.Package {
 	if importPath == "" {
 		return c.pkg
@@ -248,12 +338,18 @@ func (c *gengoCtx) Package(importPath string) gengotypes.Package {
 	return c.universe.Package(importPath)
 }
 
+func (c *gengoCtx) Implementations(iface gengotypes.TypeName) ([]gengotypes.Implementation, error) {
+	return c.universe.Implementations(iface)
+}
+
 func (c *gengoCtx) LocateInPackage(pos token.Pos) gengotypes.Package {
 	return c.universe.LocateInPackage(pos)
 }
 
 func (c *gengoCtx) Doc(typ types.Object) (Tags, []string) {
-	tags, doc := c.universe.Package(typ.Pkg().Path()).Doc(typ.Pos())
+	pkg := c.universe.Package(typ.Pkg().Path())
+
+	tags, doc := pkg.Doc(typ.Pos())
 
 	if len(doc) > 0 {
 		doc[0] = strings.TrimSpace(strings.TrimPrefix(doc[0], typ.Name()))
@@ -262,9 +358,46 @@ func (c *gengoCtx) Doc(typ types.Object) (Tags, []string) {
 		}
 	}
 
+	switch x := typ.(type) {
+	case *types.Var:
+		if x.IsField() {
+			// field tags inherit from tags of the type which field declared in
+			if owner := pkg.OwnerOf(x); owner != nil {
+				ownerTags, _ := pkg.Doc(owner.Pos())
+				return merge(c.args.Globals, c.pkgTags, ownerTags, tags), doc
+			}
+			break
+		}
+		return merge(c.args.Globals, c.pkgTags, groupDeclTags(pkg, x.Pos()), tags), doc
+	case *types.Const:
+		return merge(c.args.Globals, c.pkgTags, groupDeclTags(pkg, x.Pos()), tags), doc
+	}
+
 	return merge(c.args.Globals, c.pkgTags, tags), doc
 }
 
+// groupDeclTags returns tags of grouped declaration, like
+//
+//	// +gengo:xxx
+//	const (
+//		A = 1
+//	)
+func groupDeclTags(pkg gengotypes.Package, pos token.Pos) map[string][]string {
+	if d, ok := pkg.Decl(pos).(*ast.GenDecl); ok && d.Lparen.IsValid() {
+		tags, _ := pkg.Doc(d.Pos())
+		return tags
+	}
+	return nil
+}
+
+func (c *gengoCtx) IsFieldEnabled(f *types.Var) bool {
+	if c.gen == nil {
+		return true
+	}
+	tags, _ := c.Doc(f)
+	return !IsGeneratorDisabled(c.gen, tags)
+}
+
 func (c *gengoCtx) doGenerate(ctx corecontext.Context, g Generator) error {
 	if c.pkg == nil {
 		return nil
@@ -276,6 +409,13 @@ func (c *gengoCtx) doGenerate(ctx corecontext.Context, g Generator) error {
 		}
 	}()
 
+	pg, isPackageGenerator := g.(PackageGenerator)
+	if isPackageGenerator {
+		if err := pg.BeginPackage(c); err != nil {
+			return err
+		}
+	}
+
 	pkgTypes := c.pkg.Types()
 
 	names := make([]string, 0)
@@ -309,6 +449,145 @@ func (c *gengoCtx) doGenerate(ctx corecontext.Context, g Generator) error {
 		}
 	}
 
+	if err := c.doGenerateFuncs(ctx, g); err != nil {
+		return err
+	}
+
+	if err := c.doGenerateValues(ctx, g); err != nil {
+		return err
+	}
+
+	if isPackageGenerator {
+		// code rendered in EndPackage not belongs to any object
+		c.endSource()
+
+		if err := pg.EndPackage(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *gengoCtx) doGenerateFuncs(ctx corecontext.Context, g Generator) error {
+	fg, ok := g.(FuncGenerator)
+	if !ok {
+		return nil
+	}
+
+	funcs := c.pkg.Functions()
+
+	for _, name := range slices.Sorted(maps.Keys(funcs)) {
+		fn := funcs[name]
+
+		if tags, _ := c.Doc(fn); IsGeneratorEnabled(g, tags) {
+			if err := c.doGenerateObject(ctx, "func", fn, func() error {
+				return fg.GenerateFunc(c, fn)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	pkgTypes := c.pkg.Types()
+
+	for _, name := range slices.Sorted(maps.Keys(pkgTypes)) {
+		named, ok := pkgTypes[name].Type().(*types.Named)
+		if !ok {
+			continue
+		}
+
+		methods := slices.SortedFunc(slices.Values(c.pkg.MethodsOf(named, true)), func(a *types.Func, b *types.Func) int {
+			return strings.Compare(a.Name(), b.Name())
+		})
+
+		for _, fn := range methods {
+			if tags, _ := c.Doc(fn); IsGeneratorEnabled(g, tags) {
+				if err := c.doGenerateObject(ctx, "method", fn, func() error {
+					return fg.GenerateFunc(c, fn)
+				}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *gengoCtx) doGenerateValues(ctx corecontext.Context, g Generator) error {
+	if cg, ok := g.(ConstGenerator); ok {
+		constants := c.pkg.Constants()
+
+		for _, name := range slices.Sorted(maps.Keys(constants)) {
+			cst := constants[name]
+
+			// only package-level constants
+			if cst.Parent() != c.pkg.Pkg().Scope() {
+				continue
+			}
+
+			if tags, _ := c.Doc(cst); IsGeneratorEnabled(g, tags) {
+				if err := c.doGenerateObject(ctx, "const", cst, func() error {
+					return cg.GenerateConst(c, cst)
+				}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if vg, ok := g.(VarGenerator); ok {
+		vars := c.pkg.Vars()
+
+		for _, name := range slices.Sorted(maps.Keys(vars)) {
+			v := vars[name]
+
+			if tags, _ := c.Doc(v); IsGeneratorEnabled(g, tags) {
+				if err := c.doGenerateObject(ctx, "var", v, func() error {
+					return vg.GenerateVar(c, v)
+				}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *gengoCtx) markSource(kind string, o types.Object) {
+	if c.genfile != nil {
+		c.genfile.markSource(c.pkg.Position(o.Pos()), kind, o.Name())
+	}
+}
+
+func (c *gengoCtx) endSource() {
+	if c.genfile != nil {
+		c.genfile.endSource()
+	}
+}
+
+func (c *gengoCtx) doGenerateObject(pctx corecontext.Context, kind string, o types.Object, generate func() error) error {
+	_, l := c.l.Start(pctx, "debug: generate "+kind, slog.String("scope", o.Pkg().Path()), slog.String("type", o.Name()))
+	defer l.End()
+
+	c.markSource(kind, o)
+	defer c.endSource()
+
+	if err := generate(); err != nil {
+		if errors.Is(err, ErrSkip) {
+			return nil
+		}
+		if errors.Is(err, ErrIgnore) {
+			l.Warn(err)
+			// mark ignore to avoid remove previous generated
+			c.ignore = true
+			return nil
+		}
+		return err
+	}
+
 	return nil
 }
 
@@ -316,6 +595,9 @@ func (c *gengoCtx) doGenerateNamedType(pctx corecontext.Context, g Generator, x
 	_, l := c.l.Start(pctx, "debug: generate named", slog.String("scope", x.Obj().Pkg().Path()), slog.String("type", x.Obj().Name()))
 	defer l.End()
 
+	c.markSource("type", x.Obj())
+	defer c.endSource()
+
 	if err := g.GenerateType(c, x); err != nil {
 		if errors.Is(err, ErrSkip) {
 			return nil
@@ -336,6 +618,9 @@ func (c *gengoCtx) doGenerateAliasType(pctx corecontext.Context, g AliasGenerato
 	_, l := c.l.Start(pctx, "debug: generate alias", slog.String("scope", x.Obj().Pkg().Path()), slog.String("type", x.Obj().Name()))
 	defer l.End()
 
+	c.markSource("type", x.Obj())
+	defer c.endSource()
+
 	if err := g.GenerateAliasType(c, x); err != nil {
 		if errors.Is(err, ErrSkip) {
 			return nil
@@ -360,20 +645,43 @@ func (c *gengoCtx) New(generator Generator) Generator {
 func IsGeneratorEnabled(g Generator, tags map[string][]string) bool {
 	prefix := "gengo:" + g.Name()
 
-	enabled := false
+	if IsGeneratorDisabled(g, tags) {
+		return false
+	}
 
-	for k, values := range tags {
-		if k == prefix {
-			enabled = strings.Join(values, "") != "false"
-			return enabled
+	if _, ok := tags[prefix]; ok {
+		return true
+	}
+
+	for k := range tags {
+		if k == prefix+":skip" {
+			continue
 		}
 
 		if strings.HasPrefix(k, prefix+":") {
-			enabled = true
+			return true
 		}
 	}
 
-	return enabled
+	return false
+}
+
+// IsGeneratorDisabled check if generator disabled explicitly by
+//
+//	+gengo:<name>=false
+//	+gengo:<name>:skip
+func IsGeneratorDisabled(g Generator, tags map[string][]string) bool {
+	prefix := "gengo:" + g.Name()
+
+	if values, ok := tags[prefix]; ok && strings.Join(values, "") == "false" {
+		return true
+	}
+
+	if values, ok := tags[prefix+":skip"]; ok && strings.Join(values, "") != "false" {
+		return true
+	}
+
+	return false
 }
 
 type Tags map[string][]string
@@ -0,0 +1,47 @@
+package gengo_test
+
+import (
+	"go/types"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	"github.com/octohelm/gengo/pkg/gengo/snippet"
+	testingx "github.com/octohelm/x/testing"
+)
+
+var renderGenerateCases = []generateCase{
+	{
+		name: "render with unused args",
+		args: gengo.GeneratorArgs{
+			Globals: map[string][]string{
+				"gengo:unused": {""},
+			},
+			OutputFileBaseName: "zz_generated",
+			Force:              true,
+		},
+		gen: &stubGen{
+			name: "unused",
+			generateType: func(g *stubGen, c gengo.Context, named *types.Named) error {
+				if named.Obj().Name() != "Obj" {
+					return nil
+				}
+
+				c.RenderT(`
+var _ = @Type{}
+`, snippet.IDArg("Type", named.Obj()), snippet.ValueArg("unused", 1))
+
+				return c.(gengo.ErrReporter).Err()
+			},
+		},
+		expect: func(t *testing.T, r *generateRun, err error) {
+			// unused args should not be fatal
+			testingx.Expect(t, err, testingx.BeNil[error]())
+
+			data, err := os.ReadFile("zz_generated.unused.go")
+			testingx.Expect(t, err, testingx.BeNil[error]())
+			testingx.Expect(t, strings.Contains(string(data), "var _ = Obj{}"), testingx.BeTrue())
+		},
+	},
+}
@@ -0,0 +1,94 @@
+package extgen_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	"github.com/octohelm/gengo/pkg/gengo/extgen"
+	"github.com/octohelm/gengo/pkg/gengo/internal/fixture"
+	"github.com/octohelm/gengo/pkg/gengo/snippet"
+	testingx "github.com/octohelm/x/testing"
+)
+
+func TestMain(m *testing.M) {
+	// act as the out-of-process generator
+	if os.Getenv("GENGO_EXTGEN_TEST") != "" {
+		extgen.Main(func(req *extgen.Request, sw gengo.SnippetWriter) error {
+			sw.Render(snippet.T(`
+var _ = @Sprintf
+`, snippet.IDArg("Sprintf", "fmt.Sprintf")))
+
+			for _, t := range req.Package.Types {
+				if len(t.TypeParams) > 0 {
+					continue
+				}
+
+				sw.Render(snippet.T(`
+func (@Type) ExtFields() map[string]any {
+	return map[string]any{
+		@fields
+	}
+}
+`, snippet.Args{
+					"Type": snippet.ID(t.Ref),
+					"fields": snippet.Snippets(func(yield func(snippet.Snippet) bool) {
+						for _, f := range t.Fields {
+							if !f.Exported {
+								continue
+							}
+							if !yield(snippet.Sprintf("%v: new(%T),\n", f.Name, f.Type)) {
+								return
+							}
+						}
+					}),
+				}))
+			}
+			return nil
+		})
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestGenerator(t *testing.T) {
+	t.Setenv("GENGO_EXTGEN_TEST", "1")
+
+	exe, err := os.Executable()
+	testingx.Expect(t, err, testingx.BeNil[error]())
+
+	fixture.Package(t, "../../../testdata/a/b")
+
+	c, err := gengo.NewContext(&gengo.GeneratorArgs{
+		Entrypoint: []string{
+			".",
+		},
+		Globals: map[string][]string{
+			"gengo:ext": {""},
+		},
+		OutputFileBaseName: "zz_generated_ext",
+	})
+	testingx.Expect(t, err, testingx.BeNil[error]())
+
+	err = c.Execute(context.Background(), extgen.New("ext", exe))
+	testingx.Expect(t, err, testingx.BeNil[error]())
+
+	data, err := os.ReadFile("zz_generated_ext.ext.go")
+	testingx.Expect(t, err, testingx.BeNil[error]())
+	testingx.Expect(t, strings.Contains(string(data), `fmt "fmt"`), testingx.BeTrue())
+	testingx.Expect(t, strings.Contains(string(data), `func (Third) ExtFields() map[string]any`), testingx.BeTrue())
+	testingx.Expect(t, strings.Contains(string(data), `"Path":    new(string),`), testingx.BeTrue())
+}
+
+func TestCode(t *testing.T) {
+	code, err := extgen.CodeOf(snippet.T(`var _ @Type`, snippet.IDArg("Type", "github.com/x/y.Name")))
+	testingx.Expect(t, err, testingx.BeNil[error]())
+
+	testingx.Expect(t, code, testingx.Equal(extgen.Code{
+		{Code: "var _ "},
+		{Ref: "github.com/x/y.Name"},
+	}))
+}
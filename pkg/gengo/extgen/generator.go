@@ -0,0 +1,167 @@
+package extgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"os"
+	"os/exec"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	"github.com/octohelm/gengo/pkg/gengo/model"
+	"github.com/octohelm/gengo/pkg/gengo/snippet"
+)
+
+// New create generator which spawns the command as the out-of-process generator
+func New(name string, command string, args ...string) *Generator {
+	return &Generator{
+		name:    name,
+		command: command,
+		args:    args,
+	}
+}
+
+type Generator struct {
+	name    string
+	command string
+	args    []string
+
+	types []*types.Named
+}
+
+var _ gengo.PackageGenerator = &Generator{}
+
+func (g *Generator) Name() string {
+	return g.name
+}
+
+func (g *Generator) New(c gengo.Context) gengo.Generator {
+	return New(g.name, g.command, g.args...)
+}
+
+func (g *Generator) GenerateType(c gengo.Context, named *types.Named) error {
+	g.types = append(g.types, named)
+	return nil
+}
+
+func (g *Generator) BeginPackage(c gengo.Context) error {
+	g.types = nil
+	return nil
+}
+
+func (g *Generator) EndPackage(c gengo.Context) error {
+	if len(g.types) == 0 {
+		return nil
+	}
+
+	pkg, err := packageOf(c, g.types)
+	if err != nil {
+		return err
+	}
+
+	resp, err := g.call(c, &Request{
+		Generator: g.name,
+		Package:   pkg,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Render(resp.Code)
+
+	return nil
+}
+
+func (g *Generator) call(c gengo.Context, req *Request) (*Response, error) {
+	stdin := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(stdin).Encode(req); err != nil {
+		return nil, err
+	}
+
+	stdout := bytes.NewBuffer(nil)
+
+	cmd := exec.Command(g.command, g.args...)
+	cmd.Dir = c.Package("").SourceDir()
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("run `%s` failed: %w", g.command, err)
+	}
+
+	resp := &Response{}
+	if err := json.NewDecoder(stdout).Decode(resp); err != nil {
+		return nil, fmt.Errorf("invalid response of `%s`: %w", g.command, err)
+	}
+
+	if resp.Error != "" {
+		return nil, fmt.Errorf("`%s` generate failed: %s", g.command, resp.Error)
+	}
+
+	return resp, nil
+}
+
+func packageOf(c gengo.Context, namedTypes []*types.Named) (*Package, error) {
+	pkg := c.Package("")
+
+	p := &Package{
+		Path: pkg.Pkg().Path(),
+		Name: pkg.Pkg().Name(),
+	}
+
+	for _, named := range namedTypes {
+		t, err := typeOf(model.TypeOf(c, named))
+		if err != nil {
+			return nil, fmt.Errorf("serialize type %s failed: %w", named.Obj().Name(), err)
+		}
+		p.Types = append(p.Types, t)
+	}
+
+	return p, nil
+}
+
+func typeOf(m *model.Type) (*Type, error) {
+	underlying, err := CodeOf(snippet.ID(m.Underlying))
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Type{
+		Name:       m.Name,
+		Doc:        m.Doc,
+		Tags:       m.Tags,
+		Ref:        m.Obj.Pkg().Path() + "." + m.Name,
+		TypeParams: m.TypeParams,
+		Underlying: underlying,
+	}
+
+	for _, f := range m.Fields {
+		fieldType, err := CodeOf(snippet.ID(f.Type))
+		if err != nil {
+			return nil, err
+		}
+
+		t.Fields = append(t.Fields, &Field{
+			Name:     f.Name,
+			Doc:      f.Doc,
+			Tags:     f.Tags,
+			Type:     fieldType,
+			Tag:      f.Tag,
+			Embedded: f.Embedded,
+			Exported: f.Exported,
+		})
+	}
+
+	for _, m := range m.Methods {
+		t.Methods = append(t.Methods, &Method{
+			Name:    m.Name,
+			Doc:     m.Doc,
+			Tags:    m.Tags,
+			PtrRecv: m.PtrRecv,
+		})
+	}
+
+	return t, nil
+}
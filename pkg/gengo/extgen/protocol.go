@@ -0,0 +1,181 @@
+/*
+Package extgen provides protocol and sdk for out-of-process generators.
+
+gengo spawns the generator executable once for each package,
+writes Request as json into its stdin, and reads Response as json from its stdout.
+Response.Code will be written into the generated file of the generator, `<OutputFileBaseName>.<generator name>.go`,
+same as in-process generators, additional files are not supported.
+Type references in codes are carried as Fragment.Ref,
+so imports will be tracked by gengo same as in-process generators.
+*/
+package extgen
+
+import (
+	"bytes"
+	"context"
+	"iter"
+	"strings"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	"github.com/octohelm/gengo/pkg/gengo/snippet"
+	"github.com/octohelm/gengo/pkg/namer"
+	gengotypes "github.com/octohelm/gengo/pkg/types"
+)
+
+type Request struct {
+	// Generator name
+	Generator string `json:"generator"`
+	// Package to generate for
+	Package *Package `json:"package"`
+}
+
+type Response struct {
+	// Code rendered
+	Code Code `json:"code,omitempty"`
+	// Error message when generate failed
+	Error string `json:"error,omitempty"`
+}
+
+type Package struct {
+	// Path import path
+	Path string `json:"path"`
+	// Name package name
+	Name string `json:"name"`
+	// Types enabled for the generator,
+	// fields disabled for the generator by tags are excluded
+	Types []*Type `json:"types,omitempty"`
+}
+
+type Type struct {
+	Name string              `json:"name"`
+	Doc  []string            `json:"doc,omitempty"`
+	Tags map[string][]string `json:"tags,omitempty"`
+	// Ref of the type self, like `github.com/x/y.Name`
+	Ref string `json:"ref"`
+	// TypeParams names of type params for generic type
+	TypeParams []string `json:"typeParams,omitempty"`
+	// Underlying type literal
+	Underlying Code      `json:"underlying"`
+	Fields     []*Field  `json:"fields,omitempty"`
+	Methods    []*Method `json:"methods,omitempty"`
+}
+
+type Field struct {
+	Name     string              `json:"name"`
+	Doc      []string            `json:"doc,omitempty"`
+	Tags     map[string][]string `json:"tags,omitempty"`
+	Type     Code                `json:"type"`
+	Tag      string              `json:"tag,omitempty"`
+	Embedded bool                `json:"embedded,omitempty"`
+	Exported bool                `json:"exported,omitempty"`
+}
+
+type Method struct {
+	Name    string              `json:"name"`
+	Doc     []string            `json:"doc,omitempty"`
+	Tags    map[string][]string `json:"tags,omitempty"`
+	PtrRecv bool                `json:"ptrRecv,omitempty"`
+}
+
+// Fragment of code, only one of Code or Ref should be set.
+type Fragment struct {
+	// Code raw code
+	Code string `json:"code,omitempty"`
+	// Ref type reference, like `github.com/x/y.Name`, which will be named and imported
+	Ref string `json:"ref,omitempty"`
+}
+
+// Code is a list of Fragment, could be used as snippet.Snippet
+type Code []Fragment
+
+func (c Code) IsNil() bool {
+	return len(c) == 0
+}
+
+func (c Code) Frag(ctx context.Context) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, f := range c {
+			if f.Ref != "" {
+				for code := range snippet.ID(f.Ref).Frag(ctx) {
+					if !yield(code) {
+						return
+					}
+				}
+				continue
+			}
+
+			if !yield(f.Code) {
+				return
+			}
+		}
+	}
+}
+
+func (c Code) String() string {
+	b := &strings.Builder{}
+	for _, f := range c {
+		if f.Ref != "" {
+			b.WriteString(f.Ref)
+			continue
+		}
+		b.WriteString(f.Code)
+	}
+	return b.String()
+}
+
+// CodeOf render snippet to Code,
+// type references will be kept as Fragment.Ref instead of naming.
+func CodeOf(s snippet.Snippet) (Code, error) {
+	b := bytes.NewBuffer(nil)
+
+	sw := NewSnippetWriter(b)
+	sw.Render(s)
+
+	if err := errOf(sw); err != nil {
+		return nil, err
+	}
+
+	return codeFrom(b.String()), nil
+}
+
+// NewSnippetWriter create snippet writer, which writes type references as refMarker wrapped
+func NewSnippetWriter(b *bytes.Buffer) gengo.SnippetWriter {
+	return gengo.NewSnippetWriter(b, namer.NameSystems{
+		"raw": &refNamer{},
+	})
+}
+
+// errOf returns errors reported during rendering of the snippet writer
+func errOf(sw gengo.SnippetWriter) error {
+	if r, ok := sw.(gengo.ErrReporter); ok {
+		return r.Err()
+	}
+	return nil
+}
+
+const refMarker = "\x00"
+
+type refNamer struct{}
+
+func (refNamer) Name(typeName gengotypes.TypeName) string {
+	return refMarker + typeName.Pkg().Path() + "." + typeName.Name() + refMarker
+}
+
+func codeFrom(s string) Code {
+	code := Code{}
+
+	for i, part := range strings.Split(s, refMarker) {
+		if part == "" {
+			continue
+		}
+
+		if i%2 == 1 {
+			code = append(code, Fragment{Ref: part})
+			continue
+		}
+
+		code = append(code, Fragment{Code: part})
+	}
+
+	return code
+}
@@ -0,0 +1,48 @@
+package extgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+)
+
+// GenerateFunc renders codes for the package into SnippetWriter.
+// snippet.ID with type ref or types.Type could be used as in-process generators,
+// the imports will be handled by gengo.
+type GenerateFunc func(req *Request, sw gengo.SnippetWriter) error
+
+// Main serve with stdin and stdout, should be called in main of the generator executable
+func Main(generate GenerateFunc) {
+	if err := Serve(os.Stdin, os.Stdout, generate); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Serve read Request from r, and write Response into w
+func Serve(r io.Reader, w io.Writer, generate GenerateFunc) error {
+	req := &Request{}
+	if err := json.NewDecoder(r).Decode(req); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+
+	resp := &Response{}
+
+	b := bytes.NewBuffer(nil)
+
+	sw := NewSnippetWriter(b)
+
+	if err := generate(req, sw); err != nil {
+		resp.Error = err.Error()
+	} else if err := errOf(sw); err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Code = codeFrom(b.String())
+	}
+
+	return json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,103 @@
+package gengo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// FormatError reports generated code which could not be parsed
+type FormatError struct {
+	// Filename of generated file
+	Filename string
+	// Generator name
+	Generator string
+	// Source full unformatted source
+	Source []byte
+	// Errors all parse errors
+	Errors []*SourceError
+}
+
+func (e *FormatError) Error() string {
+	b := &strings.Builder{}
+
+	_, _ = fmt.Fprintf(b, "gengo:%s generated invalid %s:", e.Generator, filepath.Base(e.Filename))
+
+	for _, err := range e.Errors {
+		_, _ = fmt.Fprintf(b, "\n\t%s: %s", err.Pos, err.Msg)
+	}
+
+	return b.String()
+}
+
+// SourceError is an error at position of source, with surrounding lines
+type SourceError struct {
+	Pos token.Position
+	Msg string
+	// Lines surrounding Pos.Line
+	Lines []SourceLine
+}
+
+type SourceLine struct {
+	Line int
+	Text string
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+// String prints surrounding lines with caret under the error column
+func (e *SourceError) String() string {
+	b := &strings.Builder{}
+
+	_, _ = fmt.Fprintln(b, e.Pos)
+
+	for _, l := range e.Lines {
+		_, _ = fmt.Fprintf(b, "%d\t%s\n", l.Line, l.Text)
+
+		if l.Line == e.Pos.Line {
+			_, _ = fmt.Fprintf(b, "\t%s↑\n", strings.Repeat(" ", max(e.Pos.Column-1, 0)))
+		}
+	}
+
+	_, _ = fmt.Fprint(b, e.Msg)
+
+	return b.String()
+}
+
+const sourceErrorContextLines = 5
+
+func newFormatError(filename string, generator string, src []byte, err error) error {
+	var errList scanner.ErrorList
+	if !errors.As(err, &errList) {
+		return err
+	}
+
+	lines := bytes.Split(src, []byte("\n"))
+
+	e := &FormatError{
+		Filename:  filename,
+		Generator: generator,
+		Source:    src,
+	}
+
+	for _, se := range errList {
+		sourceErr := &SourceError{
+			Pos: se.Pos,
+			Msg: se.Msg,
+		}
+
+		for l := max(se.Pos.Line-sourceErrorContextLines, 1); l <= min(se.Pos.Line+sourceErrorContextLines, len(lines)); l++ {
+			sourceErr.Lines = append(sourceErr.Lines, SourceLine{Line: l, Text: string(lines[l-1])})
+		}
+
+		e.Errors = append(e.Errors, sourceErr)
+	}
+
+	return e
+}
@@ -0,0 +1,64 @@
+package gengo
+
+import (
+	"go/format"
+
+	gformat "mvdan.cc/gofumpt/format"
+)
+
+// Formatter formats source of generated file
+type Formatter interface {
+	Format(c Context, filename string, src []byte) ([]byte, error)
+}
+
+// FormatterFunc adapts func as Formatter
+type FormatterFunc func(c Context, filename string, src []byte) ([]byte, error)
+
+func (fn FormatterFunc) Format(c Context, filename string, src []byte) ([]byte, error) {
+	return fn(c, filename, src)
+}
+
+// FormattingGenerator could be implemented by generator to override formatters of GeneratorArgs
+type FormattingGenerator interface {
+	// Formatters for files generated by the generator
+	Formatters() []Formatter
+}
+
+// DefaultFormatters used when no formatters configured
+var DefaultFormatters = []Formatter{
+	GofumptFormatter(false),
+}
+
+// GofmtFormatter formats as gofmt
+func GofmtFormatter() Formatter {
+	return FormatterFunc(func(c Context, filename string, src []byte) ([]byte, error) {
+		return format.Source(src)
+	})
+}
+
+// GofumptFormatter formats as gofumpt with Go version and module path of the package
+func GofumptFormatter(extraRules bool) Formatter {
+	return FormatterFunc(func(c Context, filename string, src []byte) ([]byte, error) {
+		m := c.Package("").Module()
+
+		return gformat.Source(src, gformat.Options{
+			LangVersion: "go" + m.GoVersion,
+			ModulePath:  m.Path,
+			ExtraRules:  extraRules,
+		})
+	})
+}
+
+func formattersOf(args *GeneratorArgs, g Generator) []Formatter {
+	if fg, ok := g.(FormattingGenerator); ok {
+		if formatters := fg.Formatters(); formatters != nil {
+			return formatters
+		}
+	}
+
+	if args.Formatters != nil {
+		return args.Formatters
+	}
+
+	return DefaultFormatters
+}
@@ -0,0 +1,52 @@
+package gengo_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	testingx "github.com/octohelm/x/testing"
+)
+
+func newFormattedGen() *stubGen {
+	return &stubGen{
+		name: "formatted",
+		formatters: []gengo.Formatter{
+			gengo.GofmtFormatter(),
+			gengo.FormatterFunc(func(c gengo.Context, filename string, src []byte) ([]byte, error) {
+				return append([]byte("//go:build !ignore\n\n"), src...), nil
+			}),
+		},
+		generateType: renderForObj(`
+func (v *@Type) Formatted() {}
+`),
+	}
+}
+
+var formatterGenerateCases = []generateCase{
+	{
+		name: "generator formatters",
+		args: gengo.GeneratorArgs{
+			Globals: map[string][]string{
+				"gengo:formatted": {""},
+			},
+			OutputFileBaseName: "zz_generated_formatter",
+			Force:              true,
+			Formatters: []gengo.Formatter{
+				gengo.FormatterFunc(func(c gengo.Context, filename string, src []byte) ([]byte, error) {
+					return nil, errors.New("should be overridden by generator")
+				}),
+			},
+		},
+		gen: newFormattedGen(),
+		expect: func(t *testing.T, r *generateRun, err error) {
+			testingx.Expect(t, err, testingx.BeNil[error]())
+
+			data, err := os.ReadFile("zz_generated_formatter.formatted.go")
+			testingx.Expect(t, err, testingx.BeNil[error]())
+			testingx.Expect(t, strings.HasPrefix(string(data), "//go:build !ignore\n"), testingx.BeTrue())
+		},
+	},
+}
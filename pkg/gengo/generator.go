@@ -3,6 +3,8 @@ package gengo
 import (
 	"errors"
 	"go/types"
+
+	"github.com/octohelm/gengo/pkg/namer"
 )
 
 var (
@@ -21,6 +23,23 @@ type GeneratorArgs struct {
 	All bool
 	// Force enabled, will force generate without cache
 	Force bool
+	// Plugins go plugin files to load generators from, see LoadPlugin
+	Plugins []string
+	// UniverseOutputPackage is the import path of package which UniverseGenerator writes into,
+	// default is the first entrypoint package in import path order
+	UniverseOutputPackage string
+	// ImportNaming is the strategy to name imports of generated files,
+	// default is namer.DefaultImportNaming
+	ImportNaming namer.ImportNaming
+	// TypeCheck enabled, will type-check generated file with other files of the package before writing,
+	// generated file with type errors will not be written
+	TypeCheck bool
+	// Formatters pipeline to format generated files, default is DefaultFormatters.
+	// Generator could override it by implementing FormattingGenerator
+	Formatters []Formatter
+	// SourceMap emits line directives or comments mapping generated code back to the objects which produce it,
+	// and writes machine-readable SourceMap as `<generated file>.map`
+	SourceMap SourceMapMode
 }
 
 type Generator interface {
@@ -33,10 +52,63 @@ type Generator interface {
 type AliasGenerator interface {
 	// Name generator name
 	Name() string
-
+	// GenerateAliasType do generate for each alias type
 	GenerateAliasType(Context, *types.Alias) error
 }
 
+// FuncGenerator could be implemented by Generator,
+// to generate for each function or method
+type FuncGenerator interface {
+	// Name generator name
+	Name() string
+	// GenerateFunc do generate for each function or method
+	GenerateFunc(Context, *types.Func) error
+}
+
+// ConstGenerator could be implemented by Generator,
+// to generate for each constant
+type ConstGenerator interface {
+	// Name generator name
+	Name() string
+	// GenerateConst do generate for each constant
+	GenerateConst(Context, *types.Const) error
+}
+
+// VarGenerator could be implemented by Generator,
+// to generate for each package-level variable
+type VarGenerator interface {
+	// Name generator name
+	Name() string
+	// GenerateVar do generate for each package-level variable
+	GenerateVar(Context, *types.Var) error
+}
+
+// PackageGenerator could be implemented by Generator,
+// to set up state or emit package-wide codes once for each package
+type PackageGenerator interface {
+	// Name generator name
+	Name() string
+	// BeginPackage called before generating for types of package
+	BeginPackage(Context) error
+	// EndPackage called after all types of package generated
+	EndPackage(Context) error
+}
+
+// UniverseGenerator could be implemented by Generator,
+// to aggregate across all processed packages with a single instance for each Execute,
+// which is created by GeneratorNewer or as zero value of the registered one.
+//
+// Generate hooks will be called with packages and types in every processed package even cached,
+// but the codes rendered in these calls will be dropped.
+// At the end of Execute, GenerateUniverse will be called once to write the aggregate output
+// into GeneratorArgs.UniverseOutputPackage.
+type UniverseGenerator interface {
+	// Name generator name
+	Name() string
+	// GenerateUniverse do generate for the aggregate output
+	GenerateUniverse(Context) error
+}
+
 type GeneratorNewer interface {
 	// New generator
 	New(c Context) Generator
@@ -2,9 +2,17 @@ package gengo_test
 
 import (
 	"context"
+	"go/types"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/octohelm/gengo/devpkg/templategen"
 	"github.com/octohelm/gengo/pkg/gengo"
+	"github.com/octohelm/gengo/pkg/gengo/internal/fixture"
+	"github.com/octohelm/gengo/pkg/gengo/snippet"
+	testingx "github.com/octohelm/x/testing"
 
 	_ "github.com/octohelm/gengo/devpkg/deepcopygen"
 	_ "github.com/octohelm/gengo/devpkg/defaultergen"
@@ -19,12 +27,271 @@ func TestPkgGenerator(t *testing.T) {
 		},
 		OutputFileBaseName: "zz_generated",
 		All:                true,
+		TypeCheck:          true,
 	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	if err := templategen.Register("../../testdata/a/b/" + templategen.DefaultPattern); err != nil {
+		t.Fatal(err)
+	}
+
 	if err := c.Execute(context.Background(), gengo.GetRegisteredGenerators()...); err != nil {
 		t.Fatal(err)
 	}
+
+	data, err := os.ReadFile("../../testdata/a/b/zz_generated.fieldnames.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testingx.Expect(t, string(data), testingx.Equal(`/*
+Package b GENERATED BY gengo:fieldnames
+DON'T EDIT THIS FILE
+*/
+package b
+
+func (*Obj) FieldNames() []string {
+	return []string{
+		"Name",
+		"Internal",
+		"SubObj",
+	}
+}
+
+func (*Obj) New() any {
+	return new(Obj)
+}
+`))
+}
+
+// newGenerateRun creates run for a copy of testdata/a/b in temp dir as working dir,
+// to avoid generated files written into the shared testdata.
+// prepares could modify the copied package before loading.
+func newGenerateRun(t *testing.T, args *gengo.GeneratorArgs, gen gengo.Generator, prepares ...func(dir string) error) *generateRun {
+	t.Helper()
+
+	dir := fixture.Package(t, "../../testdata/a/b")
+
+	for _, prepare := range prepares {
+		if err := prepare(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	args.Entrypoint = []string{"."}
+
+	r := &generateRun{t: t, args: args, gen: gen}
+	r.Reload()
+
+	return r
+}
+
+type generateRun struct {
+	t    *testing.T
+	args *gengo.GeneratorArgs
+	gen  gengo.Generator
+	c    gengo.Executor
+}
+
+func (r *generateRun) Execute() error {
+	return r.ExecuteWith(r.gen)
+}
+
+func (r *generateRun) ExecuteWith(g gengo.Generator) error {
+	return r.c.Execute(context.Background(), g)
+}
+
+// Reload creates context with current args, to load files generated by previous runs
+func (r *generateRun) Reload() {
+	r.t.Helper()
+
+	c, err := gengo.NewContext(r.args)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	r.c = c
+}
+
+// generateCase executes gen once in fixture package,
+// expect could check result and execute again by the run.
+type generateCase struct {
+	name     string
+	args     gengo.GeneratorArgs
+	prepares []func(dir string) error
+	gen      gengo.Generator
+	expect   func(t *testing.T, r *generateRun, err error)
+}
+
+func TestGenerate(t *testing.T) {
+	cases := slices.Concat(
+		objectGenerateCases,
+		universeGenerateCases,
+		renderGenerateCases,
+		typeCheckGenerateCases,
+		formatterGenerateCases,
+		sourceMapGenerateCases,
+	)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := c.args
+			r := newGenerateRun(t, &args, c.gen, c.prepares...)
+			c.expect(t, r, r.Execute())
+		})
+	}
+}
+
+// stubGen is the generator shared by generate cases, which generates by optional hooks.
+// New creates a copy with same hooks for each run.
+type stubGen struct {
+	name       string
+	formatters []gengo.Formatter
+
+	generateType   func(g *stubGen, c gengo.Context, named *types.Named) error
+	generateObject func(g *stubGen, c gengo.Context, kind string, obj types.Object) error
+	endPackage     func(g *stubGen, c gengo.Context) error
+
+	// collected by hooks of current instance
+	collected []string
+}
+
+var (
+	_ gengo.FuncGenerator       = &stubGen{}
+	_ gengo.ConstGenerator      = &stubGen{}
+	_ gengo.VarGenerator        = &stubGen{}
+	_ gengo.PackageGenerator    = &stubGen{}
+	_ gengo.FormattingGenerator = &stubGen{}
+)
+
+func (g *stubGen) Name() string {
+	return g.name
+}
+
+func (g *stubGen) New(c gengo.Context) gengo.Generator {
+	n := *g
+	n.collected = nil
+	return &n
+}
+
+func (g *stubGen) Formatters() []gengo.Formatter {
+	return g.formatters
+}
+
+func (g *stubGen) BeginPackage(c gengo.Context) error {
+	return nil
+}
+
+func (g *stubGen) EndPackage(c gengo.Context) error {
+	if g.endPackage == nil {
+		return nil
+	}
+	return g.endPackage(g, c)
+}
+
+func (g *stubGen) GenerateType(c gengo.Context, named *types.Named) error {
+	if g.generateType == nil {
+		return nil
+	}
+	return g.generateType(g, c, named)
+}
+
+func (g *stubGen) GenerateFunc(c gengo.Context, fn *types.Func) error {
+	return g.doGenerateObject(c, "func", fn)
+}
+
+func (g *stubGen) GenerateConst(c gengo.Context, cst *types.Const) error {
+	return g.doGenerateObject(c, "const", cst)
+}
+
+func (g *stubGen) GenerateVar(c gengo.Context, v *types.Var) error {
+	return g.doGenerateObject(c, "var", v)
+}
+
+func (g *stubGen) doGenerateObject(c gengo.Context, kind string, obj types.Object) error {
+	if g.generateObject == nil {
+		return nil
+	}
+	return g.generateObject(g, c, kind, obj)
+}
+
+// renderForObj renders code with @Type of Obj only
+func renderForObj(code string) func(g *stubGen, c gengo.Context, named *types.Named) error {
+	return func(g *stubGen, c gengo.Context, named *types.Named) error {
+		if named.Obj().Name() != "Obj" {
+			return nil
+		}
+
+		c.RenderT(code, snippet.IDArg("Type", named.Obj()))
+
+		return nil
+	}
+}
+
+type testGen struct{}
+
+func (*testGen) Name() string {
+	return "test"
+}
+
+func (*testGen) GenerateType(c gengo.Context, named *types.Named) error {
+	return nil
+}
+
+func TestIsGeneratorEnabled(t *testing.T) {
+	g := &testGen{}
+
+	cases := []struct {
+		tags     gengo.Tags
+		enabled  bool
+		disabled bool
+	}{
+		{tags: gengo.Tags{}, enabled: false, disabled: false},
+		{tags: gengo.Tags{"gengo:test": {""}}, enabled: true, disabled: false},
+		{tags: gengo.Tags{"gengo:test:opt": {"x"}}, enabled: true, disabled: false},
+		{tags: gengo.Tags{"gengo:test": {"false"}}, enabled: false, disabled: true},
+		{tags: gengo.Tags{"gengo:test": {""}, "gengo:test:skip": {""}}, enabled: false, disabled: true},
+		{tags: gengo.Tags{"gengo:test": {""}, "gengo:test:skip": {"false"}}, enabled: true, disabled: false},
+		{tags: gengo.Tags{"gengo:test:skip": {"false"}}, enabled: false, disabled: false},
+	}
+
+	for _, c := range cases {
+		testingx.Expect(t, gengo.IsGeneratorEnabled(g, c.tags), testingx.Be(c.enabled))
+		testingx.Expect(t, gengo.IsGeneratorDisabled(g, c.tags), testingx.Be(c.disabled))
+	}
+}
+
+var objectGenerateCases = []generateCase{
+	{
+		name: "generate for funcs and values",
+		args: gengo.GeneratorArgs{
+			OutputFileBaseName: "zz_generated_record",
+		},
+		gen: &stubGen{
+			name: "record",
+			generateObject: func(g *stubGen, c gengo.Context, kind string, obj types.Object) error {
+				g.collected = append(g.collected, kind+" "+obj.Name())
+				return nil
+			},
+			endPackage: func(g *stubGen, c gengo.Context) error {
+				c.RenderT(`
+var recorded = @recorded
+`, snippet.ValueArg("recorded", g.collected))
+				return nil
+			},
+		},
+		expect: func(t *testing.T, r *generateRun, err error) {
+			testingx.Expect(t, err, testingx.BeNil[error]())
+
+			data, err := os.ReadFile("zz_generated_record.record.go")
+			testingx.Expect(t, err, testingx.BeNil[error]())
+			testingx.Expect(t, strings.Contains(string(data), `var recorded = []string{
+	"func V",
+	"const ValueA",
+	"const ValueB",
+	"var DefaultObj",
+}`), testingx.BeTrue())
+		},
+	},
 }
@@ -8,36 +8,63 @@ import (
 	"go/ast"
 	"go/format"
 	"go/parser"
-	"go/scanner"
 	"go/token"
 	"io"
 	"os"
 	"path"
+	"slices"
 	"sort"
-	"strings"
+	"strconv"
 
 	"github.com/octohelm/gengo/pkg/gengo/internal"
 	"github.com/octohelm/gengo/pkg/gengo/snippet"
 	"github.com/octohelm/gengo/pkg/namer"
-	gformat "mvdan.cc/gofumpt/format"
+	"golang.org/x/tools/go/ast/astutil"
 )
 
-func newGenfile(name string) *genfile {
+func newGenfile(name string, naming namer.ImportNaming) *genfile {
 	return &genfile{
 		name:    name,
-		imports: namer.NewDefaultImportTracker(),
+		imports: namer.NewStableImportTracker(namer.NewImportTracker(naming)),
 		body:    bytes.NewBuffer(nil),
 	}
 }
 
 type genfile struct {
-	name    string
-	body    *bytes.Buffer
-	imports namer.ImportTracker
+	name       string
+	body       *bytes.Buffer
+	imports    namer.StableImportTracker
+	formatters []Formatter
+
+	sourceMap     SourceMapMode
+	sources       []sourceEntry
+	pendingSource bool
+	openSource    bool
 
 	SnippetWriter
 }
 
+func (ff *genfile) configure(args *GeneratorArgs, g Generator) {
+	ff.formatters = formattersOf(args, g)
+	ff.sourceMap = args.SourceMap
+}
+
+func (ff *genfile) Err() error {
+	if r, ok := ff.SnippetWriter.(ErrReporter); ok {
+		return r.Err()
+	}
+	return nil
+}
+
+func (ff *genfile) Render(s snippet.Snippet) {
+	if s == nil || s.IsNil() {
+		return
+	}
+
+	ff.flushSourceMarker()
+	ff.SnippetWriter.Render(s)
+}
+
 func (c *genfile) IsZero() bool {
 	return c.body == nil || c.body.Len() == 0
 }
@@ -46,6 +73,21 @@ func (ff *genfile) InitWith(c Context) error {
 	pkg := c.Package("")
 	pkgPath := pkg.Pkg().Path()
 
+	// avoid import alias shadowing or colliding with top-level identifiers of the package
+	ff.imports.Reserve(pkg.Pkg().Scope().Names()...)
+
+	// reuse import aliases which the package already uses
+	for _, f := range pkg.Files() {
+		for _, spec := range f.Imports {
+			if spec.Name == nil || spec.Name.Name == "_" || spec.Name.Name == "." {
+				continue
+			}
+			if importPath, err := strconv.Unquote(spec.Path.Value); err == nil {
+				ff.imports.Prefer(importPath, spec.Name.Name)
+			}
+		}
+	}
+
 	ff.SnippetWriter = NewSnippetWriter(ff.body, map[string]namer.Namer{
 		"raw": namer.NewRawNamer(pkgPath, ff.imports),
 	})
@@ -74,73 +116,82 @@ DON'T EDIT THIS FILE
 package %s
 `, pkgName, ff.name, pkgName)
 
+	// aliases assigned after all imports collected, to keep them stable
 	writeImports(src, ff.imports.Imports())
 
-	if _, err := io.Copy(src, ff.body); err != nil {
+	if _, err := src.Write(ff.imports.Replace(ff.body.Bytes())); err != nil {
 		return err
 	}
 
 	filename := path.Join(c.Package("").SourceDir(), ff.Filename(args))
 
-	data := src.Bytes()
-
-	lines := bytes.Split(data, []byte("\n"))
-
 	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, filename, data, parser.ParseComments|parser.SkipObjectResolution|parser.AllErrors)
+	// objects resolved for pruneUnusedImports
+	file, err := parser.ParseFile(fset, filename, src.Bytes(), parser.ParseComments|parser.AllErrors)
 	if err != nil {
-		var sl scanner.ErrorList
-		if errors.As(err, &sl) {
-			for i := range sl {
-				if i > 0 {
-					// only should first error
-					break
-				}
-
-				l := sl[i].Pos.Line
-				fmt.Println(sl[i].Pos)
-				for i := l - 10; i < l; i++ {
-					if i > 0 {
-						fmt.Printf("%d\t%s\n", i+1, string(lines[i]))
-					}
-				}
-
-				col := sl[i].Pos.Column - 1
-				if col < 0 {
-					col = 0
-				}
-				fmt.Printf("\t%s↑\n", strings.Repeat(" ", col))
-				fmt.Println(sl[i].Msg)
-				fmt.Println()
-			}
-		}
-		return err
+		return newFormatError(filename, ff.name, src.Bytes(), err)
 	}
 
-	m := c.Package("").Module()
+	pruneUnusedImports(fset, file)
 
 	ast.SortImports(fset, file)
 
-	gformat.File(fset, file, gformat.Options{
-		LangVersion: "go" + m.GoVersion,
-		ModulePath:  m.Path,
-		ExtraRules:  false,
-	})
+	out := bytes.NewBuffer(nil)
+	if err := format.Node(out, fset, file); err != nil {
+		return err
+	}
 
-	f, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
-	if err != nil {
-		if os.IsNotExist(err) {
-			f, err = os.Create(filename)
-			if err != nil {
-				return err
-			}
+	code := out.Bytes()
+
+	for _, formatter := range ff.formatters {
+		formatted, err := formatter.Format(c, filename, code)
+		if err != nil {
+			return newFormatError(filename, ff.name, code, err)
+		}
+		code = formatted
+	}
+
+	code, sm := ff.resolveSourceMap(filename, code)
+
+	if args.TypeCheck {
+		if err := typeCheck(c, ff.name, filename, code); err != nil {
+			return err
 		}
+	}
+
+	if err := os.WriteFile(filename, code, 0o666); err != nil {
 		return err
 	}
 
-	defer f.Close()
+	return writeSourceMap(filename, sm)
+}
+
+// pruneUnusedImports drops imports which not referenced,
+// like imports registered by fragments which finally rendered empty.
+// file should be parsed with objects resolved,
+// idents resolved to local objects, like shadowing vars or params, are not references of imports.
+func pruneUnusedImports(fset *token.FileSet, file *ast.File) {
+	used := map[string]bool{}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil {
+				used[x.Name] = true
+			}
+		}
+		return true
+	})
+
+	for _, spec := range slices.Clone(file.Imports) {
+		if spec.Name == nil || spec.Name.Name == "_" || spec.Name.Name == "." {
+			continue
+		}
 
-	return format.Node(f, fset, file)
+		if !used[spec.Name.Name] {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			astutil.DeleteNamedImport(fset, file, spec.Name.Name, importPath)
+		}
+	}
 }
 
 func merge(tagsList ...map[string][]string) map[string][]string {
@@ -181,6 +232,12 @@ type SnippetWriter interface {
 	Render(snippet snippet.Snippet)
 }
 
+// ErrReporter could be implemented by SnippetWriter and Context,
+// to return errors reported during rendering
+type ErrReporter interface {
+	Err() error
+}
+
 func NewSnippetWriter(w io.Writer, ns namer.NameSystems) SnippetWriter {
 	return &snippetWriter{
 		Writer: w,
@@ -188,26 +245,41 @@ func NewSnippetWriter(w io.Writer, ns namer.NameSystems) SnippetWriter {
 	}
 }
 
+var _ ErrReporter = &snippetWriter{}
+
 type snippetWriter struct {
-	ns namer.NameSystems
+	ns     namer.NameSystems
+	errs   []error
+	dumper *internal.Dumper
 
 	io.Writer
 }
 
+func (sw *snippetWriter) Err() error {
+	return errors.Join(sw.errs...)
+}
+
 func (sw *snippetWriter) Dumper() *internal.Dumper {
-	if rawNamer, ok := sw.ns["raw"]; ok {
-		return internal.NewDumper(rawNamer)
+	if sw.dumper == nil {
+		if rawNamer, ok := sw.ns["raw"]; ok {
+			sw.dumper = internal.NewDumper(rawNamer)
+		}
 	}
-	return nil
+	return sw.dumper
 }
 
-func (sw *snippetWriter) Render(snippet snippet.Snippet) {
-	if snippet == nil {
+func (sw *snippetWriter) Render(s snippet.Snippet) {
+	if s == nil {
 		return
 	}
 
-	if !snippet.IsNil() {
-		for code := range snippet.Frag(internal.DumperContext.Inject(context.Background(), sw.Dumper())) {
+	if !s.IsNil() {
+		ctx := internal.DumperContext.Inject(context.Background(), sw.Dumper())
+		ctx = snippet.WithErrorReporter(ctx, func(err error) {
+			sw.errs = append(sw.errs, err)
+		})
+
+		for code := range s.Frag(ctx) {
 			_, _ = io.WriteString(sw.Writer, code)
 		}
 	}
@@ -0,0 +1,122 @@
+package gengo
+
+import (
+	"bytes"
+	"errors"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	testingx "github.com/octohelm/x/testing"
+)
+
+func TestPruneUnusedImports(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", `package x
+
+import (
+	bytes "bytes"
+	fmt "fmt"
+	strings "strings"
+)
+
+var _ = fmt.Sprint(bytes.Buffer{})
+`, parser.ParseComments)
+	testingx.Expect(t, err, testingx.BeNil[error]())
+
+	pruneUnusedImports(fset, file)
+
+	b := bytes.NewBuffer(nil)
+	_ = format.Node(b, fset, file)
+
+	testingx.Expect(t, b.String(), testingx.Be(`package x
+
+import (
+	bytes "bytes"
+	fmt "fmt"
+)
+
+var _ = fmt.Sprint(bytes.Buffer{})
+`))
+}
+
+func TestPruneUnusedImportsShadowed(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", `package x
+
+import (
+	fmt "fmt"
+	strings "strings"
+	url "net/url"
+)
+
+func Join(strings struct{ Sep string }) string {
+	return fmt.Sprint(strings.Sep)
+}
+
+func Parse() {
+	url := struct{ Path string }{}
+	_ = url.Path
+}
+`, parser.ParseComments)
+	testingx.Expect(t, err, testingx.BeNil[error]())
+
+	pruneUnusedImports(fset, file)
+
+	b := bytes.NewBuffer(nil)
+	_ = format.Node(b, fset, file)
+
+	testingx.Expect(t, b.String(), testingx.Be(`package x
+
+import (
+	fmt "fmt"
+)
+
+func Join(strings struct{ Sep string }) string {
+	return fmt.Sprint(strings.Sep)
+}
+
+func Parse() {
+	url := struct{ Path string }{}
+	_ = url.Path
+}
+`))
+}
+
+func TestFormatError(t *testing.T) {
+	src := []byte(`package x
+
+func X() {
+	return 1 +
+}
+
+func Y( {
+}
+`)
+
+	_, err := parser.ParseFile(token.NewFileSet(), "x.go", src, parser.AllErrors)
+	err = newFormatError("x.go", "test", src, err)
+
+	formatErr := &FormatError{}
+	testingx.Expect(t, errors.As(err, &formatErr), testingx.BeTrue())
+	testingx.Expect(t, formatErr.Generator, testingx.Be("test"))
+	testingx.Expect(t, formatErr.Source, testingx.Equal(src))
+	testingx.Expect(t, len(formatErr.Errors) > 1, testingx.BeTrue())
+
+	first := formatErr.Errors[0]
+	testingx.Expect(t, first.Pos.Line, testingx.Be(5))
+	testingx.Expect(t, first.Lines[0], testingx.Equal(SourceLine{Line: 1, Text: "package x"}))
+	testingx.Expect(t, first.String(), testingx.Be(`x.go:5:1
+1	package x
+2	
+3	func X() {
+4		return 1 +
+5	}
+	↑
+6	
+7	func Y( {
+8	}
+9	
+`+first.Msg))
+}
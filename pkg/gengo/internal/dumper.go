@@ -1,18 +1,15 @@
 package internal
 
 import (
-	"bytes"
 	"fmt"
-	"go/ast"
 	"go/types"
 	"reflect"
-	"sort"
-	"strconv"
+	"runtime"
+	"strings"
 
 	"github.com/octohelm/gengo/pkg/namer"
 	gengotypes "github.com/octohelm/gengo/pkg/types"
 	contextx "github.com/octohelm/x/context"
-	reflectx "github.com/octohelm/x/reflect"
 	typesutil "github.com/octohelm/x/types"
 )
 
@@ -32,6 +29,14 @@ func (d *Dumper) Name(named gengotypes.TypeName) string {
 	return d.namer.Name(named)
 }
 
+// isLocal checks if pkgPath is the package which codes generated for
+func (d *Dumper) isLocal(pkgPath string) bool {
+	if n, ok := d.namer.(interface{ PkgPath() string }); ok {
+		return n.PkgPath() == pkgPath
+	}
+	return false
+}
+
 func (d *Dumper) ReflectTypeLit(tpe reflect.Type) string {
 	return d.TypeLit(typesutil.FromRType(tpe))
 }
@@ -40,240 +45,72 @@ func (d *Dumper) TypesTypeLit(tpe types.Type) string {
 	return d.TypeLit(typesutil.FromTType(tpe))
 }
 
-func (d *Dumper) TypeLit(tpe typesutil.Type) string {
-	if tpe.PkgPath() != "" {
-		return d.Name(gengotypes.Ref(tpe.PkgPath(), tpe.Name()))
-	}
-
-	switch tpe.Kind() {
-	case reflect.Ptr:
-		return "*" + d.TypeLit(tpe.Elem())
-	case reflect.Chan:
-		return "chan " + d.TypeLit(tpe.Elem())
-	case reflect.Struct:
-		b := bytes.NewBufferString("struct {")
-
-		for i := 0; i < tpe.NumField(); i++ {
-			f := tpe.Field(i)
-
-			if !f.Anonymous() {
-				_, _ = fmt.Fprintf(b, "%s ", f.Name())
-			}
-
-			b.WriteString(d.TypeLit(f.Type()))
-
-			if tag := f.Tag(); tag != "" {
-				_, _ = fmt.Fprintf(b, " `%s`", tag)
-			}
-
-			b.WriteString("\n")
-		}
-
-		b.WriteString("}")
-
-		return b.String()
-	case reflect.Array:
-		return fmt.Sprintf("[%d]%s", tpe.Len(), d.TypeLit(tpe.Elem()))
-	case reflect.Slice:
-		return fmt.Sprintf("[]%s", d.TypeLit(tpe.Elem()))
-	case reflect.Map:
-		return fmt.Sprintf("map[%s]%s", d.TypeLit(tpe.Key()), d.TypeLit(tpe.Elem()))
-	case reflect.Interface:
-		return "any"
-	default:
-		return tpe.String()
-	}
-}
-
-type ValueLitOpt struct {
-	SubValue    bool
-	OnInterface func(v any) string
-	OnNamedType func(v any) (string, bool)
-}
-
-type ValueLitOptFn func(o *ValueLitOpt)
-
-func OnInterface(onUnknown func(v any) string) ValueLitOptFn {
-	return func(o *ValueLitOpt) {
-		o.OnInterface = onUnknown
-	}
-}
-
-func OnNamedType(onNamedType func(v any) (string, bool)) ValueLitOptFn {
-	return func(o *ValueLitOpt) {
-		o.OnNamedType = onNamedType
+// FuncName resolves name of func value by its runtime name, like
+//
+//	github.com/x/y.Func
+//	github.com/x/y.(*T).Method
+//	github.com/x/y.T.Method
+//
+// Type args of method expression of generic type will be resolved from the receiver type,
+// but generic func could not be referenced by value, since its type args erased at runtime.
+// Func of package main will be referenced without qualifier, because package main could not be imported.
+func (d *Dumper) FuncName(rv reflect.Value) (string, error) {
+	f := runtime.FuncForPC(rv.Pointer())
+	if f == nil {
+		return "", fmt.Errorf("unsupported func %s", rv.Type())
 	}
-}
 
-func SubValue(sub bool) ValueLitOptFn {
-	return func(o *ValueLitOpt) {
-		o.SubValue = sub
-	}
+	return d.funcName(f.Name(), rv.Type())
 }
 
-var basicKinds = map[reflect.Kind]bool{
-	reflect.Bool:       true,
-	reflect.Int:        true,
-	reflect.Int8:       true,
-	reflect.Int16:      true,
-	reflect.Int32:      true,
-	reflect.Int64:      true,
-	reflect.Uint:       true,
-	reflect.Uint8:      true,
-	reflect.Uint16:     true,
-	reflect.Uint32:     true,
-	reflect.Uint64:     true,
-	reflect.Uintptr:    true,
-	reflect.Float32:    true,
-	reflect.Float64:    true,
-	reflect.Complex64:  true,
-	reflect.Complex128: true,
-}
-
-func (d *Dumper) ValueLit(in any, optFns ...ValueLitOptFn) string {
-	rv, ok := in.(reflect.Value)
-	if !ok {
-		rv = reflect.ValueOf(in)
-	}
-
-	o := &ValueLitOpt{}
-
-	for i := range optFns {
-		optFns[i](o)
-	}
-
-	if rv.Kind() == reflect.Ptr && rv.IsNil() {
-		return "nil"
-	}
-
-	tpe := rv.Type()
-
-	if tpe.PkgPath() != "" && o.OnNamedType != nil {
-		if s, ok := o.OnNamedType(rv.Interface()); ok {
-			return s
+func (d *Dumper) funcName(fullName string, tpe reflect.Type) (string, error) {
+	pkgPath, name := "", fullName
+	if i := strings.LastIndex(fullName, "/"); i > 0 {
+		if j := strings.Index(fullName[i:], "."); j > 0 {
+			pkgPath, name = fullName[0:i+j], fullName[i+j+1:]
 		}
+	} else if j := strings.Index(fullName, "."); j > 0 {
+		pkgPath, name = fullName[0:j], fullName[j+1:]
 	}
 
-	switch tpe.Kind() {
-	case reflect.Ptr:
-		kind := rv.Elem().Kind()
-		if _, ok := basicKinds[kind]; ok {
-			return fmt.Sprintf("func(v %s) *%s { return &v }(%s)", kind, kind, d.ValueLit(rv.Elem(), optFns...))
-		}
-		return fmt.Sprintf("&(%s)", d.ValueLit(rv.Elem(), optFns...))
-	case reflect.Struct:
-		buf := bytes.NewBufferString(d.ReflectTypeLit(tpe))
-		buf.WriteString(`{`)
-
-		c := 0
-
-		for i := 0; i < rv.NumField(); i++ {
-			f := rv.Field(i)
-			ft := tpe.Field(i)
-
-			if ast.IsExported(ft.Name) && !reflectx.IsEmptyValue(f) {
-				v := d.ValueLit(f, append(optFns, SubValue(true))...)
-
-				if v == "" {
-					continue
-				}
+	// dots in last segment of pkg path are escaped in runtime func name, like gopkg.in/yaml%2ev3
+	pkgPath = strings.ReplaceAll(pkgPath, "%2e", ".")
 
-				if c == 0 {
-					buf.WriteString("\n")
-				}
-
-				buf.WriteString(ft.Name)
-				buf.WriteString(":")
-				buf.WriteString(v)
-				buf.WriteString(",")
-				buf.WriteString("\n")
-
-				c++
-			}
-		}
-
-		// no field
-		if o.SubValue && c == 0 {
-			return ""
-		}
-
-		buf.WriteString(`}`)
+	if pkgPath == "" || strings.HasSuffix(name, "-fm") || strings.Contains(name, ".func") {
+		return "", fmt.Errorf("unsupported func %s, only package-level func or method expression could be referenced", fullName)
+	}
 
-		return buf.String()
-	case reflect.Map:
-		buf := bytes.NewBufferString(d.ReflectTypeLit(tpe))
-		buf.WriteString(`{`)
+	// type args of generic func or type are erased as [...]
+	plainName := strings.ReplaceAll(name, "[...]", "")
 
-		keyLits := make([]string, 0)
-		keyValues := map[string]reflect.Value{}
+	typeName, method, isMethod := strings.Cut(plainName, ".")
 
-		for _, key := range rv.MapKeys() {
-			k := d.ValueLit(key, optFns...)
-			keyLits = append(keyLits, k)
-			keyValues[k] = rv.MapIndex(key)
+	if plainName != name {
+		if !isMethod || tpe.NumIn() == 0 {
+			return "", fmt.Errorf("unsupported generic func %s, type args could not be resolved from func value", fullName)
 		}
 
-		sort.Strings(keyLits)
-
-		for i, k := range keyLits {
-			if i == 0 {
-				buf.WriteString("\n")
-			}
-
-			buf.WriteString(k)
-			buf.WriteString(":")
-			buf.WriteString(d.ValueLit(keyValues[k], optFns...))
-			buf.WriteString(",")
-			buf.WriteString("\n")
+		// receiver of method expression is the first param
+		recv := tpe.In(0)
+		if recv.Kind() == reflect.Pointer {
+			return "(" + d.ReflectTypeLit(recv) + ")." + method, nil
 		}
+		return d.ReflectTypeLit(recv) + "." + method, nil
+	}
 
-		buf.WriteString(`}`)
-		return buf.String()
-	case reflect.Slice, reflect.Array:
-		buf := bytes.NewBufferString(d.ReflectTypeLit(tpe))
-		buf.WriteString(`{`)
-
-		for i := 0; i < rv.Len(); i++ {
-			if i == 0 {
-				buf.WriteString("\n")
-			}
-
-			buf.WriteString(d.ValueLit(rv.Index(i), append(optFns, SubValue(false))...))
-			buf.WriteString(",")
-			buf.WriteString("\n")
+	ref := func(name string) string {
+		if pkgPath == "main" {
+			return name
 		}
+		return d.Name(gengotypes.Ref(pkgPath, name))
+	}
 
-		buf.WriteString(`}`)
-
-		return buf.String()
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int64:
-		return fmt.Sprintf("%d", rv.Int())
-	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint8:
-		return fmt.Sprintf("%d", rv.Uint())
-	case reflect.Int32:
-		if b, ok := rv.Interface().(rune); ok {
-			r := strconv.QuoteRune(b)
-			if len(r) == 3 {
-				return r
-			}
-		}
-		return fmt.Sprintf("%d", rv.Int())
-	case reflect.Bool:
-		return strconv.FormatBool(rv.Bool())
-	case reflect.Float32:
-		return strconv.FormatFloat(rv.Float(), 'f', -1, 32)
-	case reflect.Float64:
-		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
-	case reflect.String:
-		return strconv.Quote(rv.String())
-	case reflect.Interface:
-		if o.OnInterface != nil {
-			return o.OnInterface(rv.Interface())
+	if isMethod {
+		if strings.HasPrefix(typeName, "(*") {
+			return "(*" + ref(strings.TrimSuffix(strings.TrimPrefix(typeName, "(*"), ")")) + ")." + method, nil
 		}
-		return "nil"
-	case reflect.Invalid:
-		return "nil"
-	default:
-		panic(fmt.Errorf("%s is an unsupported type", tpe.String()))
+		return ref(typeName) + "." + method, nil
 	}
+
+	return ref(name), nil
 }
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	gengotypes "github.com/octohelm/gengo/pkg/types"
+)
+
+// ConstantLit prints constant value as typed Go literal with full precision.
+//
+// When type is a named type with an exported constant of same value in its package,
+// the qualified const ident will be used.
+func (d *Dumper) ConstantLit(tpe types.Type, v constant.Value) (string, error) {
+	if v == nil || v.Kind() == constant.Unknown {
+		return "", fmt.Errorf("unknown constant value of %s", tpe)
+	}
+
+	if named, ok := types.Unalias(tpe).(*types.Named); ok {
+		if c := lookupNamedConstant(named, v); c != nil {
+			return d.Name(gengotypes.Ref(c.Pkg().Path(), c.Name())), nil
+		}
+	}
+
+	basic, _ := types.Unalias(tpe).Underlying().(*types.Basic)
+
+	lit, isDefault, err := d.constantLit(basic, v)
+	if err != nil {
+		return "", err
+	}
+
+	switch t := tpe.(type) {
+	case nil:
+		return lit, nil
+	case *types.Basic:
+		if t.Info()&types.IsUntyped != 0 || isDefault(t) {
+			return lit, nil
+		}
+	default:
+	}
+
+	return d.TypesTypeLit(tpe) + "(" + lit + ")", nil
+}
+
+func lookupNamedConstant(named *types.Named, v constant.Value) *types.Const {
+	pkg := named.Obj().Pkg()
+	if pkg == nil {
+		return nil
+	}
+
+	scope := pkg.Scope()
+
+	// scope names are sorted, pick first matched for stable output
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !c.Exported() || !types.Identical(c.Type(), named) {
+			continue
+		}
+
+		if c.Val().Kind() == v.Kind() && constant.Compare(c.Val(), token.EQL, v) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// constantLit returns literal and checker whether the literal in the type could be written without conversion
+func (d *Dumper) constantLit(basic *types.Basic, v constant.Value) (string, func(t *types.Basic) bool, error) {
+	isKind := func(kinds ...types.BasicKind) func(t *types.Basic) bool {
+		return func(t *types.Basic) bool {
+			return slices.Contains(kinds, t.Kind())
+		}
+	}
+
+	switch v.Kind() {
+	case constant.Bool:
+		return strconv.FormatBool(constant.BoolVal(v)), isKind(types.Bool), nil
+	case constant.String:
+		return strconv.Quote(constant.StringVal(v)), isKind(types.String), nil
+	case constant.Int:
+		if basic != nil && basic.Kind() == types.UntypedRune {
+			if r, ok := constant.Int64Val(v); ok {
+				if q := strconv.QuoteRune(rune(r)); !strings.HasPrefix(q, `'\x`) {
+					return q, isKind(types.Int32), nil
+				}
+			}
+		}
+
+		lit := v.ExactString()
+
+		if basic != nil && basic.Info()&(types.IsFloat|types.IsComplex) != 0 && basic.Info()&types.IsUntyped != 0 {
+			// keep kind of untyped float
+			lit += ".0"
+		}
+
+		return lit, isKind(types.Int), nil
+	case constant.Float:
+		return ratLit(constant.Val(v)), isKind(types.Float64), nil
+	case constant.Complex:
+		return fmt.Sprintf("complex(%s, %s)", ratLit(constant.Val(constant.Real(v))), ratLit(constant.Val(constant.Imag(v)))), isKind(types.Complex128), nil
+	default:
+		return "", nil, fmt.Errorf("unsupported constant %s", v)
+	}
+}
+
+// ratLit prints float constant value in full precision,
+// value which could not be written as decimal will be printed as division, like (1.0 / 3)
+func ratLit(x any) string {
+	r := &big.Rat{}
+
+	switch v := x.(type) {
+	case int64:
+		r.SetInt64(v)
+	case *big.Int:
+		r.SetInt(v)
+	case *big.Rat:
+		r.Set(v)
+	case *big.Float:
+		v.Rat(r)
+	default:
+		return fmt.Sprint(x)
+	}
+
+	if r.IsInt() {
+		return r.Num().String() + ".0"
+	}
+
+	denom := new(big.Int).Set(r.Denom())
+
+	// exact decimal only when denom is 2^a * 5^b
+	digits := 0
+	for _, p := range []int64{2, 5} {
+		n := 0
+		bp := big.NewInt(p)
+		m := &big.Int{}
+		for {
+			q, rem := new(big.Int).QuoRem(denom, bp, m)
+			if rem.Sign() != 0 {
+				break
+			}
+			denom = q
+			n++
+		}
+		digits = max(digits, n)
+	}
+
+	if denom.Cmp(big.NewInt(1)) == 0 {
+		return r.FloatString(digits)
+	}
+
+	return "(" + r.Num().String() + ".0 / " + r.Denom().String() + ")"
+}
@@ -0,0 +1,356 @@
+package internal
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+
+	gengotypes "github.com/octohelm/gengo/pkg/types"
+	typesutil "github.com/octohelm/x/types"
+)
+
+// TypeLit prints Go syntax of type,
+// all referenced packages go through the namer.
+func (d *Dumper) TypeLit(tpe typesutil.Type) string {
+	b := &strings.Builder{}
+
+	switch x := tpe.Unwrap().(type) {
+	case types.Type:
+		d.writeTypesType(b, x)
+	case reflect.Type:
+		d.writeReflectType(b, x)
+	default:
+		b.WriteString(tpe.String())
+	}
+
+	return b.String()
+}
+
+func (d *Dumper) writeTypesType(b *strings.Builder, tpe types.Type) {
+	switch x := tpe.(type) {
+	case *types.Basic:
+		if x.Kind() == types.UnsafePointer {
+			b.WriteString(d.Name(gengotypes.Ref("unsafe", "Pointer")))
+			return
+		}
+		b.WriteString(x.Name())
+	case *types.Named:
+		d.writeTypesTypeName(b, x.Obj())
+
+		if args := x.TypeArgs(); args.Len() > 0 {
+			d.writeTypesTypeList(b, args)
+		} else if params := x.TypeParams(); params.Len() > 0 {
+			b.WriteString("[")
+			for i := range params.Len() {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(params.At(i).Obj().Name())
+			}
+			b.WriteString("]")
+		}
+	case *types.Alias:
+		d.writeTypesTypeName(b, x.Obj())
+
+		if args := x.TypeArgs(); args.Len() > 0 {
+			d.writeTypesTypeList(b, args)
+		}
+	case *types.TypeParam:
+		b.WriteString(x.Obj().Name())
+	case *types.Pointer:
+		b.WriteString("*")
+		d.writeTypesType(b, x.Elem())
+	case *types.Slice:
+		b.WriteString("[]")
+		d.writeTypesType(b, x.Elem())
+	case *types.Array:
+		_, _ = fmt.Fprintf(b, "[%d]", x.Len())
+		d.writeTypesType(b, x.Elem())
+	case *types.Map:
+		b.WriteString("map[")
+		d.writeTypesType(b, x.Key())
+		b.WriteString("]")
+		d.writeTypesType(b, x.Elem())
+	case *types.Chan:
+		elemRecvOnly := false
+		if c, ok := x.Elem().(*types.Chan); ok {
+			elemRecvOnly = c.Dir() == types.RecvOnly
+		}
+		d.writeChan(b, chanDirOf(x.Dir()), elemRecvOnly, func() {
+			d.writeTypesType(b, x.Elem())
+		})
+	case *types.Signature:
+		b.WriteString("func")
+		d.writeTypesSignature(b, x)
+	case *types.Struct:
+		b.WriteString("struct {")
+
+		for i := range x.NumFields() {
+			f := x.Field(i)
+
+			if !f.Embedded() {
+				_, _ = fmt.Fprintf(b, "%s ", f.Name())
+			}
+
+			d.writeTypesType(b, f.Type())
+
+			if tag := x.Tag(i); tag != "" {
+				_, _ = fmt.Fprintf(b, " `%s`", tag)
+			}
+
+			b.WriteString("\n")
+		}
+
+		b.WriteString("}")
+	case *types.Interface:
+		if x.NumEmbeddeds() == 0 && x.NumExplicitMethods() == 0 {
+			b.WriteString("any")
+			return
+		}
+
+		b.WriteString("interface {")
+
+		for i := range x.NumEmbeddeds() {
+			d.writeTypesType(b, x.EmbeddedType(i))
+			b.WriteString("\n")
+		}
+
+		for i := range x.NumExplicitMethods() {
+			m := x.ExplicitMethod(i)
+			b.WriteString(m.Name())
+			d.writeTypesSignature(b, m.Signature())
+			b.WriteString("\n")
+		}
+
+		b.WriteString("}")
+	case *types.Union:
+		for i := range x.Len() {
+			if i > 0 {
+				b.WriteString(" | ")
+			}
+
+			t := x.Term(i)
+			if t.Tilde() {
+				b.WriteString("~")
+			}
+			d.writeTypesType(b, t.Type())
+		}
+	case *types.Tuple:
+		for i := range x.Len() {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			d.writeTypesType(b, x.At(i).Type())
+		}
+	default:
+		b.WriteString(tpe.String())
+	}
+}
+
+func (d *Dumper) writeTypesTypeName(b *strings.Builder, tn *types.TypeName) {
+	// universe types like error, comparable, any
+	if tn.Pkg() == nil {
+		b.WriteString(tn.Name())
+		return
+	}
+	b.WriteString(d.Name(gengotypes.Ref(tn.Pkg().Path(), tn.Name())))
+}
+
+func (d *Dumper) writeTypesTypeList(b *strings.Builder, list *types.TypeList) {
+	b.WriteString("[")
+	for i := range list.Len() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		d.writeTypesType(b, list.At(i))
+	}
+	b.WriteString("]")
+}
+
+// writeTypesSignature prints signature without func keyword, receiver and type params
+func (d *Dumper) writeTypesSignature(b *strings.Builder, sig *types.Signature) {
+	params := sig.Params()
+
+	b.WriteString("(")
+	for i := range params.Len() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		t := params.At(i).Type()
+
+		if sig.Variadic() && i == params.Len()-1 {
+			b.WriteString("...")
+			if s, ok := t.(*types.Slice); ok {
+				t = s.Elem()
+			}
+		}
+
+		d.writeTypesType(b, t)
+	}
+	b.WriteString(")")
+
+	results := sig.Results()
+
+	switch results.Len() {
+	case 0:
+	case 1:
+		b.WriteString(" ")
+		d.writeTypesType(b, results.At(0).Type())
+	default:
+		b.WriteString(" (")
+		d.writeTypesType(b, results)
+		b.WriteString(")")
+	}
+}
+
+func (d *Dumper) writeReflectType(b *strings.Builder, tpe reflect.Type) {
+	if tpe.Name() != "" {
+		if tpe.PkgPath() != "" {
+			b.WriteString(d.Name(gengotypes.Ref(tpe.PkgPath(), tpe.Name())))
+			return
+		}
+
+		// predeclared types like error, int
+		if tpe.Kind() != reflect.UnsafePointer {
+			b.WriteString(tpe.Name())
+			return
+		}
+	}
+
+	switch tpe.Kind() {
+	case reflect.UnsafePointer:
+		b.WriteString(d.Name(gengotypes.Ref("unsafe", "Pointer")))
+	case reflect.Ptr:
+		b.WriteString("*")
+		d.writeReflectType(b, tpe.Elem())
+	case reflect.Slice:
+		b.WriteString("[]")
+		d.writeReflectType(b, tpe.Elem())
+	case reflect.Array:
+		_, _ = fmt.Fprintf(b, "[%d]", tpe.Len())
+		d.writeReflectType(b, tpe.Elem())
+	case reflect.Map:
+		b.WriteString("map[")
+		d.writeReflectType(b, tpe.Key())
+		b.WriteString("]")
+		d.writeReflectType(b, tpe.Elem())
+	case reflect.Chan:
+		elem := tpe.Elem()
+		elemRecvOnly := elem.Kind() == reflect.Chan && elem.Name() == "" && elem.ChanDir() == reflect.RecvDir
+		d.writeChan(b, tpe.ChanDir(), elemRecvOnly, func() {
+			d.writeReflectType(b, elem)
+		})
+	case reflect.Func:
+		b.WriteString("func")
+		d.writeReflectSignature(b, tpe)
+	case reflect.Struct:
+		b.WriteString("struct {")
+
+		for i := range tpe.NumField() {
+			f := tpe.Field(i)
+
+			if !f.Anonymous {
+				_, _ = fmt.Fprintf(b, "%s ", f.Name)
+			}
+
+			d.writeReflectType(b, f.Type)
+
+			if tag := f.Tag; tag != "" {
+				_, _ = fmt.Fprintf(b, " `%s`", tag)
+			}
+
+			b.WriteString("\n")
+		}
+
+		b.WriteString("}")
+	case reflect.Interface:
+		if tpe.NumMethod() == 0 {
+			b.WriteString("any")
+			return
+		}
+
+		// embedded interfaces are flattened as method set in reflect
+		b.WriteString("interface {")
+
+		for i := range tpe.NumMethod() {
+			m := tpe.Method(i)
+			b.WriteString(m.Name)
+			d.writeReflectSignature(b, m.Type)
+			b.WriteString("\n")
+		}
+
+		b.WriteString("}")
+	default:
+		b.WriteString(tpe.String())
+	}
+}
+
+// writeReflectSignature prints signature of func type without func keyword
+func (d *Dumper) writeReflectSignature(b *strings.Builder, tpe reflect.Type) {
+	b.WriteString("(")
+	for i := range tpe.NumIn() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		t := tpe.In(i)
+
+		if tpe.IsVariadic() && i == tpe.NumIn()-1 {
+			b.WriteString("...")
+			t = t.Elem()
+		}
+
+		d.writeReflectType(b, t)
+	}
+	b.WriteString(")")
+
+	switch tpe.NumOut() {
+	case 0:
+	case 1:
+		b.WriteString(" ")
+		d.writeReflectType(b, tpe.Out(0))
+	default:
+		b.WriteString(" (")
+		for i := range tpe.NumOut() {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			d.writeReflectType(b, tpe.Out(i))
+		}
+		b.WriteString(")")
+	}
+}
+
+func chanDirOf(dir types.ChanDir) reflect.ChanDir {
+	switch dir {
+	case types.SendOnly:
+		return reflect.SendDir
+	case types.RecvOnly:
+		return reflect.RecvDir
+	default:
+		return reflect.BothDir
+	}
+}
+
+func (d *Dumper) writeChan(b *strings.Builder, dir reflect.ChanDir, elemRecvOnly bool, writeElem func()) {
+	switch dir {
+	case reflect.SendDir:
+		b.WriteString("chan<- ")
+	case reflect.RecvDir:
+		b.WriteString("<-chan ")
+	default:
+		b.WriteString("chan ")
+	}
+
+	// chan (<-chan T) should not be parsed as chan<- chan T
+	if dir == reflect.BothDir && elemRecvOnly {
+		b.WriteString("(")
+		writeElem()
+		b.WriteString(")")
+		return
+	}
+
+	writeElem()
+}
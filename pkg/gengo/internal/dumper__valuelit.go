@@ -0,0 +1,414 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	gengotypes "github.com/octohelm/gengo/pkg/types"
+	reflectx "github.com/octohelm/x/reflect"
+)
+
+type ValueLitOpt struct {
+	SubValue bool
+	// DynamicInterface prints dynamic value of interface type, otherwise nil will be printed
+	DynamicInterface bool
+	OnInterface      func(v any) string
+	OnNamedType      func(v any) (string, bool)
+	// OnNamedConstant resolves named constant for value, like time.Second for 1000000000 of time.Duration
+	OnNamedConstant func(v any) (gengotypes.TypeName, bool)
+}
+
+type ValueLitOptFn func(o *ValueLitOpt)
+
+func OnInterface(onUnknown func(v any) string) ValueLitOptFn {
+	return func(o *ValueLitOpt) {
+		o.OnInterface = onUnknown
+	}
+}
+
+func OnNamedType(onNamedType func(v any) (string, bool)) ValueLitOptFn {
+	return func(o *ValueLitOpt) {
+		o.OnNamedType = onNamedType
+	}
+}
+
+func OnNamedConstant(onNamedConstant func(v any) (gengotypes.TypeName, bool)) ValueLitOptFn {
+	return func(o *ValueLitOpt) {
+		o.OnNamedConstant = onNamedConstant
+	}
+}
+
+func DynamicInterface(enabled bool) ValueLitOptFn {
+	return func(o *ValueLitOpt) {
+		o.DynamicInterface = enabled
+	}
+}
+
+func SubValue(sub bool) ValueLitOptFn {
+	return func(o *ValueLitOpt) {
+		o.SubValue = sub
+	}
+}
+
+var basicKinds = map[reflect.Kind]bool{
+	reflect.Bool:       true,
+	reflect.Int:        true,
+	reflect.Int8:       true,
+	reflect.Int16:      true,
+	reflect.Int32:      true,
+	reflect.Int64:      true,
+	reflect.Uint:       true,
+	reflect.Uint8:      true,
+	reflect.Uint16:     true,
+	reflect.Uint32:     true,
+	reflect.Uint64:     true,
+	reflect.Uintptr:    true,
+	reflect.Float32:    true,
+	reflect.Float64:    true,
+	reflect.Complex64:  true,
+	reflect.Complex128: true,
+	reflect.String:     true,
+}
+
+// kinds of default type of untyped constants, exclude rune which is int32
+var defaultTypes = map[reflect.Kind]bool{
+	reflect.Bool:       true,
+	reflect.Int:        true,
+	reflect.String:     true,
+	reflect.Complex128: true,
+}
+
+// ValueLit prints Go syntax of value, panic when value could not be printed
+func (d *Dumper) ValueLit(in any, optFns ...ValueLitOptFn) string {
+	s, err := d.TryValueLit(in, optFns...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// TryValueLit prints Go syntax of value,
+// returns error when value could not be printed, like pointer cycles or closures.
+func (d *Dumper) TryValueLit(in any, optFns ...ValueLitOptFn) (string, error) {
+	rv, ok := in.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(in)
+	}
+
+	o := &ValueLitOpt{}
+
+	for i := range optFns {
+		optFns[i](o)
+	}
+
+	p := &valuePrinter{
+		Dumper:  d,
+		visited: map[visit]bool{},
+	}
+
+	return p.valueLit(rv, *o)
+}
+
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type valuePrinter struct {
+	*Dumper
+	// visited pointers in current path for cycle detection
+	visited map[visit]bool
+}
+
+func (p *valuePrinter) enter(rv reflect.Value) (func(), error) {
+	v := visit{ptr: rv.Pointer(), typ: rv.Type()}
+
+	if p.visited[v] {
+		return nil, fmt.Errorf("cycle detected at %s(%#x)", rv.Type(), v.ptr)
+	}
+
+	p.visited[v] = true
+
+	return func() {
+		delete(p.visited, v)
+	}, nil
+}
+
+func (p *valuePrinter) valueLit(rv reflect.Value, o ValueLitOpt) (string, error) {
+	if !rv.IsValid() {
+		return "nil", nil
+	}
+
+	tpe := rv.Type()
+
+	switch tpe.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		if rv.IsNil() {
+			return "nil", nil
+		}
+	default:
+	}
+
+	if tpe.PkgPath() != "" && rv.CanInterface() {
+		if o.OnNamedConstant != nil {
+			if ref, ok := o.OnNamedConstant(rv.Interface()); ok {
+				return p.Name(ref), nil
+			}
+		}
+
+		if o.OnNamedType != nil {
+			if s, ok := o.OnNamedType(rv.Interface()); ok {
+				return s, nil
+			}
+		}
+	}
+
+	switch tpe.Kind() {
+	case reflect.Interface, reflect.Func:
+		// type not referenced
+	default:
+		if tpe.Name() != "" && tpe.PkgPath() != "" && !ast.IsExported(tpe.Name()) && !p.isLocal(tpe.PkgPath()) {
+			return "", fmt.Errorf("unexported type %s of another package could not be referenced", tpe)
+		}
+	}
+
+	if basicKinds[tpe.Kind()] {
+		lit, err := p.basicLit(rv)
+		if err != nil {
+			return "", err
+		}
+
+		// named basic type should be converted, like time.Duration(5000000000)
+		if tpe.PkgPath() != "" {
+			return p.ReflectTypeLit(tpe) + "(" + lit + ")", nil
+		}
+
+		return lit, nil
+	}
+
+	switch tpe.Kind() {
+	case reflect.Ptr:
+		leave, err := p.enter(rv)
+		if err != nil {
+			return "", err
+		}
+		defer leave()
+
+		elem, err := p.valueLit(rv.Elem(), o)
+		if err != nil {
+			return "", err
+		}
+
+		if basicKinds[tpe.Elem().Kind()] {
+			t := p.ReflectTypeLit(tpe.Elem())
+			return fmt.Sprintf("func(v %s) *%s { return &v }(%s)", t, t, elem), nil
+		}
+
+		return fmt.Sprintf("&(%s)", elem), nil
+	case reflect.Struct:
+		buf := bytes.NewBufferString(p.ReflectTypeLit(tpe))
+		buf.WriteString(`{`)
+
+		c := 0
+
+		for i := 0; i < rv.NumField(); i++ {
+			f := rv.Field(i)
+			ft := tpe.Field(i)
+
+			if ast.IsExported(ft.Name) && !reflectx.IsEmptyValue(f) {
+				o := o
+				o.SubValue = true
+
+				v, err := p.valueLit(f, o)
+				if err != nil {
+					return "", err
+				}
+
+				if v == "" {
+					continue
+				}
+
+				if c == 0 {
+					buf.WriteString("\n")
+				}
+
+				buf.WriteString(ft.Name)
+				buf.WriteString(":")
+				buf.WriteString(v)
+				buf.WriteString(",")
+				buf.WriteString("\n")
+
+				c++
+			}
+		}
+
+		// no field
+		if o.SubValue && c == 0 {
+			return "", nil
+		}
+
+		buf.WriteString(`}`)
+
+		return buf.String(), nil
+	case reflect.Map:
+		leave, err := p.enter(rv)
+		if err != nil {
+			return "", err
+		}
+		defer leave()
+
+		buf := bytes.NewBufferString(p.ReflectTypeLit(tpe))
+		buf.WriteString(`{`)
+
+		keyLits := make([]string, 0)
+		keyValues := map[string]reflect.Value{}
+
+		for _, key := range rv.MapKeys() {
+			k, err := p.valueLit(key, o)
+			if err != nil {
+				return "", err
+			}
+			keyLits = append(keyLits, k)
+			keyValues[k] = rv.MapIndex(key)
+		}
+
+		sort.Strings(keyLits)
+
+		for i, k := range keyLits {
+			v, err := p.valueLit(keyValues[k], o)
+			if err != nil {
+				return "", err
+			}
+
+			if i == 0 {
+				buf.WriteString("\n")
+			}
+
+			buf.WriteString(k)
+			buf.WriteString(":")
+			buf.WriteString(v)
+			buf.WriteString(",")
+			buf.WriteString("\n")
+		}
+
+		buf.WriteString(`}`)
+		return buf.String(), nil
+	case reflect.Slice, reflect.Array:
+		if tpe.Kind() == reflect.Slice {
+			leave, err := p.enter(rv)
+			if err != nil {
+				return "", err
+			}
+			defer leave()
+		}
+
+		buf := bytes.NewBufferString(p.ReflectTypeLit(tpe))
+		buf.WriteString(`{`)
+
+		for i := 0; i < rv.Len(); i++ {
+			o := o
+			o.SubValue = false
+
+			v, err := p.valueLit(rv.Index(i), o)
+			if err != nil {
+				return "", err
+			}
+
+			if i == 0 {
+				buf.WriteString("\n")
+			}
+
+			buf.WriteString(v)
+			buf.WriteString(",")
+			buf.WriteString("\n")
+		}
+
+		buf.WriteString(`}`)
+
+		return buf.String(), nil
+	case reflect.Interface:
+		if o.OnInterface != nil && rv.CanInterface() {
+			return o.OnInterface(rv.Interface()), nil
+		}
+
+		if !o.DynamicInterface {
+			return "nil", nil
+		}
+
+		elem := rv.Elem()
+
+		v, err := p.valueLit(elem, o)
+		if err != nil {
+			return "", err
+		}
+
+		// untyped constant should be converted to keep the dynamic type
+		if t := elem.Type(); t.PkgPath() == "" && basicKinds[t.Kind()] && !defaultTypes[t.Kind()] {
+			return p.ReflectTypeLit(t) + "(" + v + ")", nil
+		}
+
+		return v, nil
+	case reflect.Func:
+		return p.FuncName(rv)
+	case reflect.Chan:
+		// only channel type and buffer size could be kept
+		if c := rv.Cap(); c > 0 {
+			return fmt.Sprintf("make(%s, %d)", p.ReflectTypeLit(tpe), c), nil
+		}
+		return fmt.Sprintf("make(%s)", p.ReflectTypeLit(tpe)), nil
+	default:
+		return "", fmt.Errorf("%s is an unsupported type", tpe.String())
+	}
+}
+
+func (p *valuePrinter) basicLit(rv reflect.Value) (string, error) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint8, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Int32:
+		if rv.Type() == reflect.TypeFor[rune]() {
+			r := strconv.QuoteRune(rune(rv.Int()))
+			if len(r) == 3 {
+				return r, nil
+			}
+		}
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Float32:
+		return p.floatLit(rv.Float(), 32), nil
+	case reflect.Float64:
+		return p.floatLit(rv.Float(), 64), nil
+	case reflect.Complex64, reflect.Complex128:
+		bitSize := 64
+		if rv.Kind() == reflect.Complex64 {
+			bitSize = 32
+		}
+		c := rv.Complex()
+		return fmt.Sprintf("complex(%s, %s)", p.floatLit(real(c), bitSize), p.floatLit(imag(c), bitSize)), nil
+	case reflect.String:
+		return strconv.Quote(rv.String()), nil
+	default:
+		return "", fmt.Errorf("%s is an unsupported type", rv.Type())
+	}
+}
+
+func (p *valuePrinter) floatLit(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return p.Name(gengotypes.Ref("math", "NaN")) + "()"
+	case math.IsInf(f, 1):
+		return p.Name(gengotypes.Ref("math", "Inf")) + "(1)"
+	case math.IsInf(f, -1):
+		return p.Name(gengotypes.Ref("math", "Inf")) + "(-1)"
+	default:
+		return strconv.FormatFloat(f, 'f', -1, bitSize)
+	}
+}
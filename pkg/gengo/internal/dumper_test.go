@@ -2,13 +2,27 @@ package internal
 
 import (
 	"bytes"
+	"context"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/octohelm/gengo/pkg/namer"
+	gengotypes "github.com/octohelm/gengo/pkg/types"
+	"github.com/octohelm/x/ptr"
 	testingx "github.com/octohelm/x/testing"
 )
 
+type unexported struct {
+	Name string
+}
+
 type Item struct {
 	Name string `json:"name"`
 }
@@ -17,6 +31,18 @@ type List[T any] struct {
 	Items []T `json:"items,omitempty"`
 }
 
+func (l *List[T]) Len() int {
+	return len(l.Items)
+}
+
+func (l List[T]) IsEmpty() bool {
+	return len(l.Items) == 0
+}
+
+func Identity[T any](v T) T {
+	return v
+}
+
 func TestDumper_TypeLit(t *testing.T) {
 	d := NewDumper(namer.NewRawNamer("", namer.NewDefaultImportTracker()))
 
@@ -39,11 +65,159 @@ func TestDumper_TypeLit(t *testing.T) {
 			testingx.Equal(d.ReflectTypeLit(reflect.TypeOf(&List[List[Item]]{}))))
 	})
 
+	t.Run("ReflectTypeLit", func(t *testing.T) {
+		testingx.Expect(t, d.ReflectTypeLit(reflect.TypeOf(func(context.Context, ...string) error { return nil })), testingx.Be("func(context.Context, ...string) error"))
+		testingx.Expect(t, d.ReflectTypeLit(reflect.TypeOf((<-chan int)(nil))), testingx.Be("<-chan int"))
+		testingx.Expect(t, d.ReflectTypeLit(reflect.TypeOf((chan (<-chan int))(nil))), testingx.Be("chan (<-chan int)"))
+		testingx.Expect(t, d.ReflectTypeLit(reflect.TypeOf((*error)(nil)).Elem()), testingx.Be("error"))
+		testingx.Expect(t, d.ReflectTypeLit(reflect.TypeOf((*interface {
+			io.Reader
+			Close() error
+		})(nil)).Elem()), testingx.Be("interface {Close() error\nRead([]uint8) (int, error)\n}"))
+	})
+
+	t.Run("TypesTypeLit", func(t *testing.T) {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "x.go", `package x
+
+import (
+	"context"
+	"io"
+)
+
+type List[T any] struct {
+	Items []T
+}
+
+type Number interface {
+	~int | ~float64
+}
+
+var (
+	Fn     func(ctx context.Context, args ...string) (int, error)
+	Recv   <-chan List[string]
+	Send   chan<- int
+	Nested chan (<-chan int)
+	Iface  interface {
+		io.Reader
+		Close() error
+	}
+	Embed struct {
+		*List[int]
+		Name string `+"`json:\"name\"`"+`
+	}
+)
+
+func Sum[T Number](values ...T) T { var t T; return t }
+`, 0)
+		testingx.Expect(t, err, testingx.BeNil[error]())
+
+		pkg, err := (&types.Config{Importer: importer.ForCompiler(fset, "source", nil)}).Check("github.com/x/y", fset, []*ast.File{f}, nil)
+		testingx.Expect(t, err, testingx.BeNil[error]())
+
+		typeOf := func(name string) types.Type {
+			return pkg.Scope().Lookup(name).Type()
+		}
+
+		testingx.Expect(t, d.TypesTypeLit(typeOf("Fn")), testingx.Be("func(context.Context, ...string) (int, error)"))
+		testingx.Expect(t, d.TypesTypeLit(typeOf("Recv")), testingx.Be("<-chan y.List[string]"))
+		testingx.Expect(t, d.TypesTypeLit(typeOf("Send")), testingx.Be("chan<- int"))
+		testingx.Expect(t, d.TypesTypeLit(typeOf("Nested")), testingx.Be("chan (<-chan int)"))
+		testingx.Expect(t, d.TypesTypeLit(typeOf("Iface")), testingx.Be("interface {io.Reader\nClose() error\n}"))
+		testingx.Expect(t, d.TypesTypeLit(typeOf("Embed")), testingx.Be("struct {*y.List[int]\nName string `json:\"name\"`\n}"))
+		testingx.Expect(t, d.TypesTypeLit(typeOf("Number").Underlying()), testingx.Be("interface {~int | ~float64\n}"))
+		testingx.Expect(t, d.TypesTypeLit(typeOf("Sum")), testingx.Be("func(...T) T"))
+		testingx.Expect(t, d.TypesTypeLit(typeOf("List")), testingx.Be("y.List[T]"))
+	})
+
 	t.Run("ValueLit", func(t *testing.T) {
 		testingx.Expect(t, "&(bytes.Buffer{})", testingx.Be(d.ValueLit(reflect.ValueOf(&(bytes.Buffer{})))))
 		testingx.Expect(t, `[]string{
 "1",
 "2",
 }`, testingx.Be(d.ValueLit(reflect.ValueOf([]string{"1", "2"}))))
+
+		d := NewDumper(namer.NewRawNamer("", namer.NewDefaultImportTracker()))
+
+		testingx.Expect(t, d.ValueLit(5*time.Second), testingx.Be("time.Duration(5000000000)"))
+		testingx.Expect(t, d.ValueLit(ptr.Ptr(5*time.Second)), testingx.Be("func(v time.Duration) *time.Duration { return &v }(time.Duration(5000000000))"))
+		testingx.Expect(t, d.ValueLit(complex(1, -2.5)), testingx.Be("complex(1, -2.5)"))
+		testingx.Expect(t, d.ValueLit([]any{float32(1.5), nil}), testingx.Be(`[]any{
+nil,
+nil,
+}`))
+		testingx.Expect(t, d.ValueLit([]any{float32(1.5), 1, "s", nil}, DynamicInterface(true)), testingx.Be(`[]any{
+float32(1.5),
+1,
+"s",
+nil,
+}`))
+		testingx.Expect(t, d.ValueLit(bytes.NewBuffer), testingx.Be("bytes.NewBuffer"))
+		testingx.Expect(t, d.ValueLit(make(chan int, 2)), testingx.Be("make(chan int, 2)"))
+		testingx.Expect(t, d.ValueLit(time.Second, OnNamedConstant(func(v any) (gengotypes.TypeName, bool) {
+			if v == time.Second {
+				return gengotypes.Ref("time", "Second"), true
+			}
+			return nil, false
+		})), testingx.Be("time.Second"))
+
+		type Node struct {
+			Next *Node
+		}
+
+		n := &Node{}
+		n.Next = n
+
+		_, err := d.TryValueLit(n)
+		testingx.Expect(t, err, testingx.NotBeNil[error]())
+
+		shared := &Item{Name: "x"}
+		_, err = d.TryValueLit([]*Item{shared, shared})
+		testingx.Expect(t, err, testingx.BeNil[error]())
+
+		_, err = d.TryValueLit(func() {})
+		testingx.Expect(t, err, testingx.NotBeNil[error]())
+
+		_, err = d.TryValueLit(unexported{Name: "x"})
+		testingx.Expect(t, err, testingx.NotBeNil[error]())
+
+		local := NewDumper(namer.NewRawNamer(reflect.TypeFor[unexported]().PkgPath(), namer.NewDefaultImportTracker()))
+		testingx.Expect(t, local.ValueLit(unexported{Name: "x"}), testingx.Be(`unexported{
+Name:"x",
+}`))
+	})
+	t.Run("FuncName", func(t *testing.T) {
+		name, err := d.FuncName(reflect.ValueOf((*List[Item]).Len))
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, name, testingx.Be("(*internal.List[internal.Item]).Len"))
+
+		name, err = d.FuncName(reflect.ValueOf(List[Item].IsEmpty))
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, name, testingx.Be("internal.List[internal.Item].IsEmpty"))
+
+		_, err = d.FuncName(reflect.ValueOf(Identity[int]))
+		testingx.Expect(t, err, testingx.NotBeNil[error]())
+
+		name, err = d.funcName("main.Run", reflect.TypeOf(func() {}))
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, name, testingx.Be("Run"))
+
+		name, err = d.funcName("main.(*Server).Serve", reflect.TypeOf(func() {}))
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, name, testingx.Be("(*Server).Serve"))
+
+		tracker := namer.NewDefaultImportTracker()
+		yd := NewDumper(namer.NewRawNamer("", tracker))
+
+		name, err = yd.funcName("gopkg.in/yaml%2ev3.Marshal", reflect.TypeOf(func() {}))
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, name, testingx.Be("yamlv3.Marshal"))
+
+		name, err = yd.funcName("gopkg.in/yaml%2ev3.(*Node).Decode", reflect.TypeOf(func() {}))
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, name, testingx.Be("(*yamlv3.Node).Decode"))
+		testingx.Expect(t, tracker.Imports(), testingx.Equal(map[string]string{
+			"gopkg.in/yaml.v3": "yamlv3",
+		}))
 	})
 }
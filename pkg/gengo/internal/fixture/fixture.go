@@ -0,0 +1,86 @@
+/*
+Package fixture prepares test packages for generator tests,
+to keep generated files out of the shared testdata.
+*/
+package fixture
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Package copies the package dir into a temp dir as a module with same import path and requirements,
+// and changes working dir into it, so the copied package could be loaded as entrypoint `.`.
+// The copied dir will be removed after the test.
+func Package(t testing.TB, dir string) string {
+	t.Helper()
+
+	src, err := filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modDir, mod := moduleOf(t, src)
+
+	rel, err := filepath.Rel(modDir, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmp := t.TempDir()
+
+	if err := os.CopyFS(tmp, os.DirFS(src)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mod.AddModuleStmt(path.Join(mod.Module.Mod.Path, filepath.ToSlash(rel))); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := mod.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmp, "go.mod"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err := os.ReadFile(filepath.Join(modDir, "go.sum"))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmp, "go.sum"), sum, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Chdir(tmp)
+
+	return tmp
+}
+
+func moduleOf(t testing.TB, dir string) (string, *modfile.File) {
+	for d := dir; ; d = filepath.Dir(d) {
+		filename := filepath.Join(d, "go.mod")
+
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			if os.IsNotExist(err) && filepath.Dir(d) != d {
+				continue
+			}
+			t.Fatal(err)
+		}
+
+		mod, err := modfile.Parse(filename, data, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return d, mod
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Package model provides the view of named types shared by generators which render with data,
+like template-file generators and out-of-process generators.
+*/
+package model
+
+import (
+	"go/types"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+)
+
+// Type model of named type
+type Type struct {
+	// Obj of type, could be used as `{{ id .Obj }}`
+	Obj  *types.TypeName
+	Name string
+	Doc  []string
+	Tags map[string][]string
+	// Underlying type, could be used as `{{ id .Underlying }}`
+	Underlying types.Type
+	// IsStruct true when underlying is struct
+	IsStruct   bool
+	TypeParams []string
+	Fields     []*Field
+	Methods    []*Method
+}
+
+type Field struct {
+	Name string
+	Doc  []string
+	Tags map[string][]string
+	// Type of field, could be used as `{{ id .Type }}`
+	Type types.Type
+	// Tag struct tag of field, could be used as `{{ structTag .Tag "json" }}`
+	Tag      string
+	Embedded bool
+	Exported bool
+}
+
+type Method struct {
+	Name    string
+	Doc     []string
+	Tags    map[string][]string
+	PtrRecv bool
+}
+
+// TypeOf collects model of named type,
+// fields disabled for current generator will be excluded, see gengo.Context.IsFieldEnabled
+func TypeOf(c gengo.Context, named *types.Named) *Type {
+	tags, doc := c.Doc(named.Obj())
+
+	t := &Type{
+		Obj:        named.Obj(),
+		Name:       named.Obj().Name(),
+		Doc:        doc,
+		Tags:       tags,
+		Underlying: named.Underlying(),
+	}
+
+	if tparams := named.TypeParams(); tparams != nil {
+		for i := 0; i < tparams.Len(); i++ {
+			t.TypeParams = append(t.TypeParams, tparams.At(i).Obj().Name())
+		}
+	}
+
+	if s, ok := named.Underlying().(*types.Struct); ok {
+		t.IsStruct = true
+
+		for i := 0; i < s.NumFields(); i++ {
+			f := s.Field(i)
+
+			if !c.IsFieldEnabled(f) {
+				continue
+			}
+
+			fieldTags, fieldDoc := c.Doc(f)
+
+			t.Fields = append(t.Fields, &Field{
+				Name:     f.Name(),
+				Doc:      fieldDoc,
+				Tags:     fieldTags,
+				Type:     f.Type(),
+				Tag:      s.Tag(i),
+				Embedded: f.Embedded(),
+				Exported: f.Exported(),
+			})
+		}
+	}
+
+	if pkg := c.Package(named.Obj().Pkg().Path()); pkg != nil {
+		for _, m := range pkg.MethodsOf(named, true) {
+			methodTags, methodDoc := c.Doc(m)
+
+			_, ptrRecv := m.Type().(*types.Signature).Recv().Type().(*types.Pointer)
+
+			t.Methods = append(t.Methods, &Method{
+				Name:    m.Name(),
+				Doc:     methodDoc,
+				Tags:    methodTags,
+				PtrRecv: ptrRecv,
+			})
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,85 @@
+package gengo
+
+import (
+	"errors"
+	"fmt"
+	"plugin"
+	"runtime"
+)
+
+// APIVersion of pkg/gengo for plugins.
+// Should be bumped when any breaking change of Generator or Context.
+//
+//	v2: Context.Implementations added
+const APIVersion = "v2"
+
+const (
+	// PluginSymbolAPIVersion symbol should be exported by plugin, as
+	//
+	//	var GengoAPIVersion = gengo.APIVersion
+	PluginSymbolAPIVersion = "GengoAPIVersion"
+	// PluginSymbolGenerators symbol should be exported by plugin, as
+	//
+	//	var GengoGenerators = []gengo.Generator{&customGen{}}
+	//
+	// or
+	//
+	//	func GengoGenerators() []gengo.Generator { return []gengo.Generator{&customGen{}} }
+	PluginSymbolGenerators = "GengoGenerators"
+)
+
+var ErrPluginMismatch = errors.New("plugin mismatch")
+
+// LoadPlugin opens go plugin built with `-buildmode=plugin`,
+// checks its APIVersion, then registers generators it exports.
+func LoadPlugin(filename string) error {
+	p, err := plugin.Open(filename)
+	if err != nil {
+		return fmt.Errorf("open plugin %s failed, plugin must be built by same go toolchain (%s) with same versions of shared modules as gengo, and cgo enabled: %w", filename, runtime.Version(), err)
+	}
+
+	return registerPlugin(filename, p)
+}
+
+// symbolLookup is the part of *plugin.Plugin used to register generators
+type symbolLookup interface {
+	Lookup(symName string) (plugin.Symbol, error)
+}
+
+func registerPlugin(filename string, p symbolLookup) error {
+	versionSym, err := p.Lookup(PluginSymbolAPIVersion)
+	if err != nil {
+		return fmt.Errorf("%w: %s missing `%s`", ErrPluginMismatch, filename, PluginSymbolAPIVersion)
+	}
+
+	version, ok := versionSym.(*string)
+	if !ok {
+		return fmt.Errorf("%w: %s exports `%s` as %T, but string required", ErrPluginMismatch, filename, PluginSymbolAPIVersion, versionSym)
+	}
+
+	if *version != APIVersion {
+		return fmt.Errorf("%w: %s built with gengo api %s, but %s required", ErrPluginMismatch, filename, *version, APIVersion)
+	}
+
+	generatorsSym, err := p.Lookup(PluginSymbolGenerators)
+	if err != nil {
+		return fmt.Errorf("%w: %s missing `%s`", ErrPluginMismatch, filename, PluginSymbolGenerators)
+	}
+
+	var generators []Generator
+
+	switch x := generatorsSym.(type) {
+	case *[]Generator:
+		generators = *x
+	case func() []Generator:
+		generators = x()
+	default:
+		return fmt.Errorf("%w: %s exports `%s` as %T, but []gengo.Generator or func() []gengo.Generator required", ErrPluginMismatch, filename, PluginSymbolGenerators, generatorsSym)
+	}
+
+	for _, g := range generators {
+		Register(g)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package gengo
+
+import (
+	"errors"
+	"go/types"
+	"plugin"
+	"testing"
+
+	testingx "github.com/octohelm/x/testing"
+)
+
+func TestLoadPlugin(t *testing.T) {
+	err := LoadPlugin("./testdata/not_exists.so")
+	testingx.Expect(t, err, testingx.NotBeNil[error]())
+}
+
+type pluginSymbols map[string]plugin.Symbol
+
+func (symbols pluginSymbols) Lookup(symName string) (plugin.Symbol, error) {
+	if s, ok := symbols[symName]; ok {
+		return s, nil
+	}
+	return nil, errors.New("symbol " + symName + " not found")
+}
+
+type pluginGen struct{}
+
+func (*pluginGen) Name() string {
+	return "plugin-test"
+}
+
+func (*pluginGen) GenerateType(c Context, named *types.Named) error {
+	return nil
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	t.Cleanup(func() {
+		delete(registeredGenerators, "plugin-test")
+	})
+
+	version := func(v string) *string {
+		return &v
+	}
+
+	t.Run("api version mismatch", func(t *testing.T) {
+		err := registerPlugin("x.so", pluginSymbols{
+			PluginSymbolAPIVersion: version("v0"),
+			PluginSymbolGenerators: &[]Generator{&pluginGen{}},
+		})
+
+		testingx.Expect(t, errors.Is(err, ErrPluginMismatch), testingx.BeTrue())
+		testingx.Expect(t, GetRegisteredGenerators("plugin-test"), testingx.HaveLen[[]Generator](0))
+	})
+
+	t.Run("missing api version", func(t *testing.T) {
+		err := registerPlugin("x.so", pluginSymbols{
+			PluginSymbolGenerators: &[]Generator{&pluginGen{}},
+		})
+
+		testingx.Expect(t, errors.Is(err, ErrPluginMismatch), testingx.BeTrue())
+	})
+
+	t.Run("invalid generators", func(t *testing.T) {
+		err := registerPlugin("x.so", pluginSymbols{
+			PluginSymbolAPIVersion: version(APIVersion),
+			PluginSymbolGenerators: &pluginGen{},
+		})
+
+		testingx.Expect(t, errors.Is(err, ErrPluginMismatch), testingx.BeTrue())
+	})
+
+	t.Run("register generators", func(t *testing.T) {
+		err := registerPlugin("x.so", pluginSymbols{
+			PluginSymbolAPIVersion: version(APIVersion),
+			PluginSymbolGenerators: func() []Generator {
+				return []Generator{&pluginGen{}}
+			},
+		})
+
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, GetRegisteredGenerators("plugin-test"), testingx.HaveLen[[]Generator](1))
+	})
+}
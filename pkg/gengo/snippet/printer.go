@@ -1,11 +1,10 @@
 package snippet
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"iter"
-	"text/scanner"
+	"strings"
 )
 
 // Sprintf
@@ -15,14 +14,24 @@ import (
 //	%T print as ident
 func Sprintf(fmt string, args ...any) Snippet {
 	return &printer{
-		fmt:  fmt,
-		args: args,
+		location: callerLocation(),
+		fmt:      fmt,
+		args:     args,
 	}
 }
 
 type printer struct {
-	fmt  string
-	args []any
+	location location
+	fmt      string
+	args     []any
+}
+
+func (p *printer) reportError(ctx context.Context, msg string) {
+	ReportError(ctx, &TemplateError{
+		Location: p.location.String(),
+		Format:   p.fmt,
+		Msg:      msg,
+	})
 }
 
 func (p *printer) IsNil() bool {
@@ -31,81 +40,109 @@ func (p *printer) IsNil() bool {
 
 func (p *printer) Frag(ctx context.Context) iter.Seq[string] {
 	return func(yield func(string) bool) {
-		s := &scanner.Scanner{}
-		s.Init(bytes.NewBuffer([]byte(p.fmt)))
-		s.Error = func(s *scanner.Scanner, msg string) {}
-
 		argIdx := 0
 
-		getArg := func() any {
+		getArg := func() (any, bool) {
 			if argIdx < len(p.args) {
 				a := p.args[argIdx]
 				argIdx++
-				return a
+				return a, true
 			}
-			panic(fmt.Errorf("missing arg %d", argIdx))
+			p.reportError(ctx, fmt.Sprintf("missing arg %d", argIdx))
+			return nil, false
 		}
 
-		c := s.Next()
-		for {
-			if c == scanner.EOF {
-				break
+		for _, seg := range compilePrintf(p.fmt) {
+			if seg.verb == 0 {
+				if !yield(seg.text) {
+					return
+				}
+				continue
 			}
 
-			switch c {
-			case '%':
-				c = s.Next()
-
-				switch c {
-				case 'T':
-					a := getArg()
-
-					switch x := a.(type) {
-					case Snippet:
-						for c := range x.Frag(ctx) {
-							if !yield(c) {
-								return
-							}
-						}
-					default:
-						for c := range ID(x).Frag(ctx) {
-							if !yield(c) {
-								return
-							}
-						}
-					}
-				case 'v':
-					a := getArg()
-
-					switch x := a.(type) {
-					case Snippet:
-						for c := range x.Frag(ctx) {
-							if !yield(c) {
-								return
-							}
-						}
-					default:
-						for c := range Value(x).Frag(ctx) {
-							if !yield(c) {
-								return
-							}
-						}
-					}
-				case '%':
-					if !yield(string(c)) {
-						return
-					}
-					continue
+			var s Snippet
+
+			switch seg.verb {
+			case 'T', 'v':
+				a, ok := getArg()
+				if !ok {
+					return
+				}
+
+				switch x := a.(type) {
+				case Snippet:
+					s = x
 				default:
-					panic(fmt.Errorf("unsupported %%%v", c))
+					if seg.verb == 'T' {
+						s = ID(x)
+					} else {
+						s = Value(x)
+					}
 				}
 			default:
-				if !yield(string(c)) {
+				p.reportError(ctx, fmt.Sprintf("unsupported verb %%%c", seg.verb))
+				return
+			}
+
+			for c := range s.Frag(ctx) {
+				if !yield(c) {
 					return
 				}
 			}
+		}
+	}
+}
 
-			c = s.Next()
+// printfSeg is literal text when verb is 0
+type printfSeg struct {
+	text string
+	verb rune
+}
+
+// compiledPrintfs caches parsed segments by format
+var compiledPrintfs = newLRUCache[[]printfSeg](compiledCacheSize)
+
+func compilePrintf(format string) []printfSeg {
+	if segs, ok := compiledPrintfs.Get(format); ok {
+		return segs
+	}
+
+	segs := make([]printfSeg, 0)
+	text := &strings.Builder{}
+
+	runes := []rune(format)
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' {
+			text.WriteRune(runes[i])
+			continue
+		}
+
+		i++
+
+		if i < len(runes) && runes[i] == '%' {
+			text.WriteRune('%')
+			continue
+		}
+
+		if text.Len() > 0 {
+			segs = append(segs, printfSeg{text: text.String()})
+			text.Reset()
+		}
+
+		if i < len(runes) {
+			segs = append(segs, printfSeg{verb: runes[i]})
+		} else {
+			// dangling %
+			segs = append(segs, printfSeg{verb: '!'})
 		}
 	}
+
+	if text.Len() > 0 {
+		segs = append(segs, printfSeg{text: text.String()})
+	}
+
+	compiledPrintfs.Put(format, segs)
+
+	return segs
 }
@@ -0,0 +1,41 @@
+package snippet
+
+import (
+	"strconv"
+	"testing"
+
+	testingx "github.com/octohelm/x/testing"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := newLRUCache[int](2)
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+
+	// touch a, then b should be evicted
+	_, _ = c.Get("a")
+	c.Put("c", 3)
+
+	_, ok := c.Get("b")
+	testingx.Expect(t, ok, testingx.BeFalse())
+
+	v, ok := c.Get("a")
+	testingx.Expect(t, ok, testingx.BeTrue())
+	testingx.Expect(t, v, testingx.Be(1))
+
+	for i := range 10 {
+		c.Put(strconv.Itoa(i), i)
+	}
+	testingx.Expect(t, c.Len(), testingx.Be(2))
+}
+
+func TestCompiledCacheBounded(t *testing.T) {
+	for i := range compiledCacheSize + 10 {
+		_, _ = compileTemplate("@a" + strconv.Itoa(i))
+		_ = compilePrintf("%v" + strconv.Itoa(i))
+	}
+
+	testingx.Expect(t, compiledTemplates.Len(), testingx.Be(compiledCacheSize))
+	testingx.Expect(t, compiledPrintfs.Len(), testingx.Be(compiledCacheSize))
+}
@@ -1,12 +1,10 @@
 package snippet
 
 import (
-	"bytes"
 	"context"
-	"fmt"
 	"iter"
-	"strings"
-	"text/scanner"
+	"maps"
+	"slices"
 )
 
 func IDArg(name string, id any) TArg {
@@ -50,10 +48,35 @@ type TArg interface {
 	Args() iter.Seq2[string, Snippet]
 }
 
+// T creates template snippet, named args will be rendered as @name.
+//
+// Syntax:
+//
+//	@name                 render arg `name`, `'` could be used to end the name, like @name'suffix
+//	@if(name) ... @end    render body when arg `name` is not nil or empty Snippets
+//	@if(name) ... @else ... @end
+//	@range(name) ... @end render body for each non-nil element of Snippets arg `name`
+//	@.                    render current element in @range body
+//	@@                    literal @
 func T(fmt string, args ...TArg) Snippet {
+	return newTemplate(callerLocation(), fmt, args...)
+}
+
+// Compile creates template snippet same as T,
+// but returns *TemplateError when template invalid or any named arg missing or unused.
+func Compile(fmt string, args ...TArg) (Snippet, error) {
+	t := newTemplate(callerLocation(), fmt, args...)
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func newTemplate(l location, fmt string, args ...TArg) *template {
 	t := &template{
-		format: fmt,
-		args:   map[string]Snippet{},
+		location: l,
+		format:   fmt,
+		args:     map[string]Snippet{},
 	}
 
 	for _, a := range args {
@@ -68,8 +91,55 @@ func T(fmt string, args ...TArg) Snippet {
 }
 
 type template struct {
-	format string
-	args   map[string]Snippet
+	location location
+	format   string
+	args     map[string]Snippet
+}
+
+func (t *template) error(msg string) *TemplateError {
+	return &TemplateError{
+		Location: t.location.String(),
+		Format:   t.format,
+		Msg:      msg,
+	}
+}
+
+// Validate checks template syntax and named args
+func (t *template) Validate() error {
+	nodes, err := compileTemplate(t.format)
+	if err != nil {
+		return t.error(err.Error())
+	}
+
+	used := map[string]bool{}
+
+	e := &TemplateError{
+		Format: t.format,
+	}
+
+	for name := range namesOf(nodes) {
+		if used[name] {
+			continue
+		}
+		used[name] = true
+
+		if _, ok := t.args[name]; !ok {
+			e.Missing = append(e.Missing, name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(t.args)) {
+		if !used[name] {
+			e.Unused = append(e.Unused, name)
+		}
+	}
+
+	if len(e.Missing) > 0 || len(e.Unused) > 0 {
+		e.Location = t.location.String()
+		return e
+	}
+
+	return nil
 }
 
 func (t *template) IsNil() bool {
@@ -78,77 +148,124 @@ func (t *template) IsNil() bool {
 
 func (t *template) Frag(ctx context.Context) iter.Seq[string] {
 	return func(yield func(string) bool) {
-		argSet := t.args
-		if argSet == nil {
-			argSet = map[string]Snippet{}
+		nodes, err := compileTemplate(t.format)
+		if err != nil {
+			ReportError(ctx, t.error(err.Error()))
+			return
 		}
 
-		s := &scanner.Scanner{}
-		s.Init(bytes.NewBuffer([]byte(strings.TrimLeft(t.format, "\n"))))
-		s.Error = func(s *scanner.Scanner, msg string) {}
+		t.render(ctx, nodes, nil, yield)
+	}
+}
 
-		c := s.Next()
-		for {
-			if c == scanner.EOF {
-				break
+func (t *template) render(ctx context.Context, nodes []tNode, dot Snippet, yield func(string) bool) bool {
+	for _, n := range nodes {
+		switch x := n.(type) {
+		case tText:
+			if !yield(string(x)) {
+				return false
+			}
+		case tArg:
+			v, ok := t.args[string(x)]
+			if !ok {
+				ReportError(ctx, &TemplateError{
+					Location: t.location.String(),
+					Format:   t.format,
+					Missing:  []string{string(x)},
+				})
+				return false
 			}
 
-			switch c {
-			case '@':
-				named := bytes.NewBuffer(nil)
+			if v == nil || v.IsNil() {
+				continue
+			}
 
-				for {
-					c = s.Next()
+			for code := range v.Frag(ctx) {
+				if !yield(code) {
+					return false
+				}
+			}
+		case tDot:
+			if dot == nil {
+				ReportError(ctx, t.error("@. must be used in @range"))
+				return false
+			}
 
-					if c == scanner.EOF || c == '\'' {
-						break
-					}
+			for code := range dot.Frag(ctx) {
+				if !yield(code) {
+					return false
+				}
+			}
+		case *tIf:
+			v, ok := t.args[x.name]
+			if !ok {
+				ReportError(ctx, &TemplateError{
+					Location: t.location.String(),
+					Format:   t.format,
+					Missing:  []string{x.name},
+				})
+				return false
+			}
 
-					if (c >= 'A' && c <= 'Z') ||
-						(c >= 'a' && c <= 'z') ||
-						(c >= '0' && c <= '9') ||
-						c == '_' {
+			body := x.then
+			if isEmpty(v) {
+				body = x.els
+			}
 
-						named.WriteRune(c)
-						continue
-					}
-					break
-				}
+			if !t.render(ctx, body, dot, yield) {
+				return false
+			}
+		case *tRange:
+			v, ok := t.args[x.name]
+			if !ok {
+				ReportError(ctx, &TemplateError{
+					Location: t.location.String(),
+					Format:   t.format,
+					Missing:  []string{x.name},
+				})
+				return false
+			}
+
+			if v == nil || v.IsNil() {
+				continue
+			}
 
-				if named.Len() > 0 {
-					name := named.String()
-
-					if v, ok := argSet[name]; ok {
-						if v.IsNil() {
-							continue
-						}
-
-						for code := range v.Frag(ctx) {
-							if !yield(code) {
-								return
-							}
-						}
-					} else {
-						panic(fmt.Sprintf("missing named arg `%s` in %s", name, t.format))
-					}
+			list, ok := v.(Snippets)
+			if !ok {
+				if !t.render(ctx, x.body, v, yield) {
+					return false
 				}
+				continue
+			}
 
-				if c == '@' {
+			for item := range list {
+				if item == nil || item.IsNil() {
 					continue
 				}
 
-				if !(c == scanner.EOF || c == '\'') {
-					if !yield(string(c)) {
-						return
-					}
-				}
-			default:
-				if !yield(string(c)) {
-					return
+				if !t.render(ctx, x.body, item, yield) {
+					return false
 				}
 			}
+		}
+	}
 
-			c = s.Next()
+	return true
+}
+
+func isEmpty(s Snippet) bool {
+	if s == nil || s.IsNil() {
+		return true
+	}
+
+	if list, ok := s.(Snippets); ok {
+		for item := range list {
+			if item != nil && !item.IsNil() {
+				return false
+			}
 		}
+		return true
 	}
+
+	return false
 }
@@ -0,0 +1,265 @@
+package snippet
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+type tNode any
+
+// tText literal text
+type tText string
+
+// tArg @name
+type tArg string
+
+// tDot @.
+type tDot struct{}
+
+// tIf @if(name) ... @else ... @end
+type tIf struct {
+	name string
+	then []tNode
+	els  []tNode
+}
+
+// tRange @range(name) ... @end
+type tRange struct {
+	name string
+	body []tNode
+}
+
+func namesOf(nodes []tNode) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		var walk func(nodes []tNode) bool
+
+		walk = func(nodes []tNode) bool {
+			for _, n := range nodes {
+				switch x := n.(type) {
+				case tArg:
+					if !yield(string(x)) {
+						return false
+					}
+				case *tIf:
+					if !yield(x.name) {
+						return false
+					}
+					if !walk(x.then) || !walk(x.els) {
+						return false
+					}
+				case *tRange:
+					if !yield(x.name) {
+						return false
+					}
+					if !walk(x.body) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+
+		walk(nodes)
+	}
+}
+
+type compiledTemplate struct {
+	nodes []tNode
+	err   error
+}
+
+// compiledTemplates caches parsed nodes by format
+var compiledTemplates = newLRUCache[*compiledTemplate](compiledCacheSize)
+
+// compileTemplate parses format once, leading newlines will be trimmed
+func compileTemplate(format string) ([]tNode, error) {
+	if c, ok := compiledTemplates.Get(format); ok {
+		return c.nodes, c.err
+	}
+
+	nodes, err := parseTemplate(strings.TrimLeft(format, "\n"))
+
+	compiledTemplates.Put(format, &compiledTemplate{nodes: nodes, err: err})
+
+	return nodes, err
+}
+
+func parseTemplate(format string) ([]tNode, error) {
+	p := &templateParser{runes: []rune(format)}
+
+	nodes, end, err := p.parse(0)
+	if err != nil {
+		return nil, err
+	}
+	if end != "" {
+		return nil, fmt.Errorf("unexpected @%s", end)
+	}
+
+	return nodes, nil
+}
+
+type templateParser struct {
+	runes []rune
+	i     int
+	// ranges depth of @range blocks, @. only allowed in @range
+	ranges int
+}
+
+func isNameRune(c rune) bool {
+	return (c >= 'A' && c <= 'Z') ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= '0' && c <= '9') ||
+		c == '_'
+}
+
+// parse nodes until @else or @end when in block, returns which one ended the nodes
+func (p *templateParser) parse(depth int) (nodes []tNode, end string, err error) {
+	text := &strings.Builder{}
+
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, tText(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.i < len(p.runes) {
+		c := p.runes[p.i]
+		p.i++
+
+		if c != '@' {
+			text.WriteRune(c)
+			continue
+		}
+
+		if p.i < len(p.runes) {
+			switch p.runes[p.i] {
+			case '@':
+				p.i++
+				text.WriteRune('@')
+				continue
+			case '.':
+				if p.ranges == 0 {
+					return nil, "", errors.New("@. must be used in @range")
+				}
+				p.i++
+				flush()
+				nodes = append(nodes, tDot{})
+				continue
+			}
+		}
+
+		start := p.i
+		for p.i < len(p.runes) && isNameRune(p.runes[p.i]) {
+			p.i++
+		}
+		name := string(p.runes[start:p.i])
+
+		if name != "" && p.i < len(p.runes) && p.runes[p.i] == '(' && (name == "if" || name == "range") {
+			flush()
+
+			argName, err := p.parseBlockArg(name)
+			if err != nil {
+				return nil, "", err
+			}
+
+			if name == "range" {
+				p.ranges++
+			}
+
+			body, bodyEnd, err := p.parse(depth + 1)
+			if err != nil {
+				return nil, "", err
+			}
+
+			if name == "range" {
+				p.ranges--
+			}
+
+			switch name {
+			case "if":
+				n := &tIf{name: argName, then: body}
+
+				if bodyEnd == "else" {
+					els, elsEnd, err := p.parse(depth + 1)
+					if err != nil {
+						return nil, "", err
+					}
+					if elsEnd != "end" {
+						return nil, "", fmt.Errorf("missing @end of @if(%s)", argName)
+					}
+					n.els = els
+				} else if bodyEnd != "end" {
+					return nil, "", fmt.Errorf("missing @end of @if(%s)", argName)
+				}
+
+				nodes = append(nodes, n)
+			case "range":
+				if bodyEnd != "end" {
+					return nil, "", fmt.Errorf("missing @end of @range(%s)", argName)
+				}
+				nodes = append(nodes, &tRange{name: argName, body: body})
+			}
+
+			continue
+		}
+
+		// `'` used to end the name
+		if p.i < len(p.runes) && p.runes[p.i] == '\'' {
+			p.i++
+		}
+
+		if name == "" {
+			continue
+		}
+
+		if depth > 0 && (name == "else" || name == "end") {
+			flush()
+			return nodes, name, nil
+		}
+
+		flush()
+		nodes = append(nodes, tArg(name))
+	}
+
+	flush()
+
+	if depth > 0 {
+		return nil, "", errors.New("missing @end")
+	}
+
+	return nodes, "", nil
+}
+
+func (p *templateParser) parseBlockArg(directive string) (string, error) {
+	// skip (
+	p.i++
+
+	start := p.i
+	for p.i < len(p.runes) && p.runes[p.i] != ')' {
+		p.i++
+	}
+
+	if p.i >= len(p.runes) {
+		return "", fmt.Errorf("missing ) of @%s", directive)
+	}
+
+	name := strings.TrimSpace(string(p.runes[start:p.i]))
+
+	// skip )
+	p.i++
+
+	for _, c := range name {
+		if !isNameRune(c) {
+			return "", fmt.Errorf("invalid arg name `%s` of @%s", name, directive)
+		}
+	}
+
+	if name == "" {
+		return "", fmt.Errorf("missing arg name of @%s", directive)
+	}
+
+	return name, nil
+}
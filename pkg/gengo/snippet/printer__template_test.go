@@ -0,0 +1,147 @@
+package snippet
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	testingx "github.com/octohelm/x/testing"
+)
+
+func TestCompile(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		_, err := Compile(`@a @b @a`, Args{
+			"a": Block("1"),
+			"b": Block("2"),
+		})
+		testingx.Expect(t, err, testingx.BeNil[error]())
+	})
+
+	t.Run("missing and unused", func(t *testing.T) {
+		_, err := Compile(`@a @b`, Args{
+			"a": Block("1"),
+			"c": Block("3"),
+		})
+
+		e := &TemplateError{}
+		testingx.Expect(t, errors.As(err, &e), testingx.BeTrue())
+		testingx.Expect(t, e.Missing, testingx.Equal([]string{"b"}))
+		testingx.Expect(t, e.Unused, testingx.Equal([]string{"c"}))
+		testingx.Expect(t, strings.Contains(e.Location, "printer__template_test.go"), testingx.BeTrue())
+	})
+}
+
+func TestCompileDot(t *testing.T) {
+	t.Run("in range", func(t *testing.T) {
+		_, err := Compile(`@range(items)@if(sep)@sep@end@.@end`, Args{
+			"items": Snippets(slices.Values([]Snippet{Block("1")})),
+			"sep":   Block(","),
+		})
+		testingx.Expect(t, err, testingx.BeNil[error]())
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		_, err := Compile(`@range(items)@.@end @.`, Args{
+			"items": Snippets(slices.Values([]Snippet{Block("1")})),
+		})
+
+		e := &TemplateError{}
+		testingx.Expect(t, errors.As(err, &e), testingx.BeTrue())
+		testingx.Expect(t, e.Msg, testingx.Be("@. must be used in @range"))
+	})
+}
+
+func TestTemplateError(t *testing.T) {
+	errs := make([]error, 0)
+
+	ctx := WithErrorReporter(context.Background(), func(err error) {
+		errs = append(errs, err)
+	})
+
+	for range T(`@a`).Frag(ctx) {
+	}
+
+	for range Sprintf(`%x`, 1).Frag(ctx) {
+	}
+
+	testingx.Expect(t, errs, testingx.HaveLen[[]error](2))
+	testingx.Expect(t, errs[0].(*TemplateError).Missing, testingx.Equal([]string{"a"}))
+	testingx.Expect(t, errs[1].(*TemplateError).Msg, testingx.Be("unsupported verb %x"))
+}
+
+func TestReportError(t *testing.T) {
+	// should not panic without reporter
+	testingx.Expect(t, render(T(`@a`)), testingx.Be(""))
+}
+
+func render(s Snippet) string {
+	b := &strings.Builder{}
+	for code := range s.Frag(context.Background()) {
+		b.WriteString(code)
+	}
+	return b.String()
+}
+
+func TestTemplate(t *testing.T) {
+	t.Run("escape", func(t *testing.T) {
+		testingx.Expect(t, render(T(`// mail to a@@b.com @name'x`, Args{"name": Block("1")})), testingx.Be("// mail to a@b.com 1x"))
+	})
+
+	t.Run("if", func(t *testing.T) {
+		tpl := `@if(a)a=@a@else(none)@end`
+
+		testingx.Expect(t, render(T(tpl, Args{"a": Block("1")})), testingx.Be("a=1"))
+		testingx.Expect(t, render(T(tpl, Args{"a": Block("")})), testingx.Be("(none)"))
+		testingx.Expect(t, render(T(tpl, Args{"a": Snippets(slices.Values([]Snippet{}))})), testingx.Be("(none)"))
+	})
+
+	t.Run("range", func(t *testing.T) {
+		items := Snippets(slices.Values([]Snippet{Block("1"), Block(""), Block("2")}))
+
+		testingx.Expect(t, render(T(`[@range(items)@if(sep)@sep@end@.,@end]`, Args{
+			"items": items,
+			"sep":   Block(""),
+		})), testingx.Be("[1,2,]"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := Compile(`@if(a) missing end`, Args{"a": Block("1")})
+		testingx.Expect(t, err, testingx.NotBeNil[error]())
+
+		_, err = Compile(`@range(a) @else @end`, Args{"a": Block("1")})
+		testingx.Expect(t, err, testingx.NotBeNil[error]())
+	})
+}
+
+func TestSprintf(t *testing.T) {
+	testingx.Expect(t, render(Sprintf("100%% %T", Block("x"))), testingx.Be("100% x"))
+}
+
+func BenchmarkTemplate(b *testing.B) {
+	fields := make([]Snippet, 0, 200)
+	for range 200 {
+		fields = append(fields, T(`
+out.@fieldName = in.@fieldName
+`, Args{
+			"fieldName": Block("Field"),
+		}))
+	}
+
+	s := T(`
+func (in *@Type) DeepCopyInto(out *@Type) {
+	@fields
+}
+`, Args{
+		"Type":   Block("Type"),
+		"fields": Snippets(slices.Values(fields)),
+	})
+
+	b.ResetTimer()
+
+	for range b.N {
+		for range s.Frag(context.Background()) {
+		}
+	}
+}
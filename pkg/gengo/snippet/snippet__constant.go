@@ -0,0 +1,47 @@
+package snippet
+
+import (
+	"context"
+	"go/constant"
+	"go/types"
+	"iter"
+
+	"github.com/octohelm/gengo/pkg/gengo/internal"
+)
+
+// Constant creates snippet of constant value as typed Go literal, like `Gender(1)`,
+// or qualified const ident when the named type has an exported constant of same value.
+// Untyped, big and float constants keep full precision.
+func Constant(tpe types.Type, v constant.Value) Snippet {
+	return &constantValue{tpe: tpe, v: v}
+}
+
+// TypeAndValue creates snippet of constant value of types.TypeAndValue, see Constant
+func TypeAndValue(tv types.TypeAndValue) Snippet {
+	return Constant(tv.Type, tv.Value)
+}
+
+type constantValue struct {
+	tpe types.Type
+	v   constant.Value
+}
+
+func (c *constantValue) IsNil() bool {
+	return c.v == nil
+}
+
+func (c *constantValue) Frag(ctx context.Context) iter.Seq[string] {
+	d := internal.DumperContext.From(ctx)
+
+	return func(yield func(string) bool) {
+		s, err := d.ConstantLit(c.tpe, c.v)
+		if err != nil {
+			ReportError(ctx, err)
+			return
+		}
+
+		if !yield(s) {
+			return
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package snippet
+
+import (
+	"context"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/octohelm/gengo/pkg/gengo/internal"
+	"github.com/octohelm/gengo/pkg/namer"
+	testingx "github.com/octohelm/x/testing"
+)
+
+func TestConstant(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "x.go", `package z
+
+type Gender int
+
+const (
+	GenderUnknown Gender = iota
+	GenderMale
+)
+
+type Ratio float32
+
+const (
+	unnamed      = Gender(5)
+	Big          = 1 << 100
+	Third        = 1.0 / 3
+	Half         = 0.5
+	UntypedFloat = 2.0
+	Float32      = float32(1.5)
+	Uint8        = uint8(3)
+	ratio2       = Ratio(2)
+	Complex      = 1 + 2.5i
+	Rune         = 'x'
+	Str          = "s\n"
+	Bool         = true
+	Int          = 1
+)
+`, 0)
+	testingx.Expect(t, err, testingx.BeNil[error]())
+
+	pkg, err := (&types.Config{Importer: importer.Default()}).Check("github.com/x/z", fset, []*ast.File{f}, nil)
+	testingx.Expect(t, err, testingx.BeNil[error]())
+
+	ctx := internal.DumperContext.Inject(context.Background(), internal.NewDumper(namer.NewRawNamer("github.com/x/y", namer.NewDefaultImportTracker())))
+
+	renderConst := func(name string) string {
+		c := pkg.Scope().Lookup(name).(*types.Const)
+
+		b := &strings.Builder{}
+		for code := range Fragments(ctx, Constant(c.Type(), c.Val())) {
+			b.WriteString(code)
+		}
+		return b.String()
+	}
+
+	cases := map[string]string{
+		"GenderMale":   "z.GenderMale",
+		"unnamed":      "z.Gender(5)",
+		"Big":          "1267650600228229401496703205376",
+		"Third":        "(1.0 / 3)",
+		"Half":         "0.5",
+		"UntypedFloat": "2.0",
+		"Float32":      "float32(1.5)",
+		"Uint8":        "uint8(3)",
+		"ratio2":       "z.Ratio(2.0)",
+		"Complex":      "complex(1.0, 2.5)",
+		"Rune":         "'x'",
+		"Str":          `"s\n"`,
+		"Bool":         "true",
+		"Int":          "1",
+	}
+
+	for name, expect := range cases {
+		t.Run(name, func(t *testing.T) {
+			testingx.Expect(t, renderConst(name), testingx.Be(expect))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package snippet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strings"
+
+	contextx "github.com/octohelm/x/context"
+)
+
+var errorReporterContext = contextx.New[func(err error)]()
+
+// WithErrorReporter inject reporter to receive errors during rendering
+func WithErrorReporter(ctx context.Context, report func(err error)) context.Context {
+	return errorReporterContext.Inject(ctx, report)
+}
+
+// ReportError reports error during rendering to the reporter injected by WithErrorReporter,
+// will be logged by slog when no reporter injected.
+func ReportError(ctx context.Context, err error) {
+	if report, ok := errorReporterContext.MayFrom(ctx); ok {
+		report(err)
+		return
+	}
+	slog.ErrorContext(ctx, "render snippet failed", slog.Any("err", err))
+}
+
+// TemplateError of template or printf format, with the location where it created
+type TemplateError struct {
+	// Location file:line where the template created
+	Location string
+	// Format of template
+	Format string
+	// Missing names of args missing
+	Missing []string
+	// Unused names of args unused
+	Unused []string
+	// Msg other error message
+	Msg string
+}
+
+func (e *TemplateError) Error() string {
+	b := &strings.Builder{}
+
+	b.WriteString("invalid template")
+	if e.Location != "" {
+		b.WriteString(" at ")
+		b.WriteString(e.Location)
+	}
+
+	if len(e.Missing) > 0 {
+		_, _ = fmt.Fprintf(b, ", missing args %v", e.Missing)
+	}
+
+	if len(e.Unused) > 0 {
+		_, _ = fmt.Fprintf(b, ", unused args %v", e.Unused)
+	}
+
+	if e.Msg != "" {
+		b.WriteString(", ")
+		b.WriteString(e.Msg)
+	}
+
+	_, _ = fmt.Fprintf(b, ":\n%s", e.Format)
+
+	return b.String()
+}
+
+var pkgPath = reflect.TypeFor[TemplateError]().PkgPath()
+
+// gengo and snippet funcs will be skipped when resolving location
+var skipPkgPrefixes = []string{
+	pkgPath + ".",
+	strings.TrimSuffix(pkgPath, "/snippet") + ".",
+}
+
+type location [4]uintptr
+
+func callerLocation() (l location) {
+	runtime.Callers(3, l[:])
+	return
+}
+
+func (l location) String() string {
+	n := 0
+	for n < len(l) && l[n] != 0 {
+		n++
+	}
+
+	frames := runtime.CallersFrames(l[:n])
+
+	for {
+		frame, more := frames.Next()
+
+		skip := false
+		for _, prefix := range skipPkgPrefixes {
+			if strings.HasSuffix(frame.File, "_test.go") {
+				break
+			}
+			if strings.HasPrefix(frame.Function, prefix) {
+				skip = true
+				break
+			}
+		}
+
+		if !skip && frame.File != "" {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
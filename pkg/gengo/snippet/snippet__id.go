@@ -19,7 +19,12 @@ func PkgExpose(pkgPath string, expose string) Snippet {
 	}
 }
 
+// PkgExposeOf creates snippet of type of x,
+// or func self when x is func or method expression like `pkg.Func` or `(*pkg.T).Method`.
 func PkgExposeOf(x any) Snippet {
+	if rv := reflect.ValueOf(x); rv.Kind() == reflect.Func {
+		return ID(x)
+	}
 	return pkgExpose(reflect.TypeOf(x))
 }
 
@@ -39,7 +44,7 @@ func pkgExpose(tp reflect.Type) Snippet {
 	}
 
 	if tp.Kind() == reflect.Func {
-		panic(fmt.Errorf("unsupported %s, which cannot get pkgPath and name", tp))
+		panic(fmt.Errorf("unsupported %s, which cannot get pkgPath and name, use PkgExposeOf with the func value instead", tp))
 	}
 
 	return &pkgExposer{
@@ -105,6 +110,25 @@ func (i *ident) Frag(ctx context.Context) iter.Seq[string] {
 				return
 			}
 			return
+		case *types.Func:
+			if !yield(objectName(d, x)) {
+				return
+			}
+			return
+		case *types.Var:
+			if x.IsField() || (x.Pkg() != nil && x.Parent() != x.Pkg().Scope()) {
+				ReportError(ctx, fmt.Errorf("unsupported %s, only package-level var could be referenced", x))
+				return
+			}
+			if !yield(objectName(d, x)) {
+				return
+			}
+			return
+		case *types.Const:
+			if !yield(objectName(d, x)) {
+				return
+			}
+			return
 		case gengotypes.TypeName:
 			if !yield(d.Name(x)) {
 				return
@@ -121,7 +145,39 @@ func (i *ident) Frag(ctx context.Context) iter.Seq[string] {
 			}
 			return
 		default:
-			panic(fmt.Sprintf("unspported %T", x))
+			if rv := reflect.ValueOf(x); rv.Kind() == reflect.Func {
+				name, err := d.FuncName(rv)
+				if err != nil {
+					ReportError(ctx, err)
+					return
+				}
+				if !yield(name) {
+					return
+				}
+				return
+			}
+
+			ReportError(ctx, fmt.Errorf("unsupported %T as ID", x))
 		}
 	}
 }
+
+// objectName of func, var or const
+func objectName(d *internal.Dumper, o types.Object) string {
+	// builtin or universe
+	if o.Pkg() == nil {
+		return o.Name()
+	}
+
+	if fn, ok := o.(*types.Func); ok {
+		if recv := fn.Signature().Recv(); recv != nil {
+			// method expression
+			if p, ok := recv.Type().(*types.Pointer); ok {
+				return "(*" + d.TypesTypeLit(p.Elem()) + ")." + fn.Name()
+			}
+			return d.TypesTypeLit(recv.Type()) + "." + fn.Name()
+		}
+	}
+
+	return d.Name(gengotypes.Ref(o.Pkg().Path(), o.Name()))
+}
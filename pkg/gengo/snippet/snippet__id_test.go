@@ -1,12 +1,99 @@
 package snippet
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/octohelm/gengo/pkg/gengo/internal"
+	"github.com/octohelm/gengo/pkg/namer"
+	testingx "github.com/octohelm/x/testing"
 )
 
 func Test_pkgExpose(t *testing.T) {
 	tpe := reflect.TypeOf(Test_pkgExpose)
 	fmt.Println(tpe)
 }
+
+func TestID(t *testing.T) {
+	renderID := func(x any) (string, error) {
+		var err error
+
+		ctx := WithErrorReporter(context.Background(), func(e error) {
+			err = e
+		})
+		ctx = internal.DumperContext.Inject(ctx, internal.NewDumper(namer.NewRawNamer("github.com/x/y", namer.NewDefaultImportTracker())))
+
+		b := &strings.Builder{}
+		for code := range Fragments(ctx, ID(x)) {
+			b.WriteString(code)
+		}
+		return b.String(), err
+	}
+
+	pkg := types.NewPackage("github.com/x/z", "z")
+	named := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "T", nil), types.Typ[types.Int], nil)
+
+	t.Run("func value", func(t *testing.T) {
+		s, err := renderID(bytes.NewBuffer)
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, s, testingx.Be("bytes.NewBuffer"))
+	})
+
+	t.Run("method expression", func(t *testing.T) {
+		s, err := renderID((*bytes.Buffer).String)
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, s, testingx.Be("(*bytes.Buffer).String"))
+	})
+
+	t.Run("closure", func(t *testing.T) {
+		_, err := renderID(func() {})
+		testingx.Expect(t, err, testingx.NotBeNil[error]())
+	})
+
+	t.Run("types.Func", func(t *testing.T) {
+		fn := types.NewFunc(token.NoPos, pkg, "Do", types.NewSignatureType(nil, nil, nil, nil, nil, false))
+		s, err := renderID(fn)
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, s, testingx.Be("z.Do"))
+
+		recv := types.NewVar(token.NoPos, pkg, "t", types.NewPointer(named))
+		method := types.NewFunc(token.NoPos, pkg, "Do", types.NewSignatureType(recv, nil, nil, nil, nil, false))
+		s, err = renderID(method)
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, s, testingx.Be("(*z.T).Do"))
+	})
+
+	t.Run("types.Var", func(t *testing.T) {
+		v := types.NewVar(token.NoPos, pkg, "Default", named)
+		pkg.Scope().Insert(v)
+
+		s, err := renderID(v)
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, s, testingx.Be("z.Default"))
+
+		_, err = renderID(types.NewField(token.NoPos, pkg, "F", named, false))
+		testingx.Expect(t, err, testingx.NotBeNil[error]())
+	})
+
+	t.Run("types.Const", func(t *testing.T) {
+		s, err := renderID(types.NewConst(token.NoPos, pkg, "Max", named, constant.MakeInt64(1)))
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, s, testingx.Be("z.Max"))
+
+		s, err = renderID(types.Universe.Lookup("true"))
+		testingx.Expect(t, err, testingx.BeNil[error]())
+		testingx.Expect(t, s, testingx.Be("true"))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := renderID(1)
+		testingx.Expect(t, err, testingx.NotBeNil[error]())
+	})
+}
@@ -0,0 +1,90 @@
+package snippet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	goprinter "go/printer"
+	"go/token"
+	"iter"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"weak"
+)
+
+// Node create snippet from ast node,
+// like *ast.FuncDecl, ast.Expr or ast.Stmt built by generators.
+//
+// Types or idents referred by IDExpr will be named by the raw namer as ID does,
+// so imports will be tracked. The node will not be modified during rendering.
+func Node(node ast.Node) Snippet {
+	return &nodeSnippet{node: node}
+}
+
+// IDExpr create ast.Expr which references to v, v could be any value accepted by ID
+func IDExpr(v any) ast.Expr {
+	ident := ast.NewIdent(fmt.Sprintf("_gengo_id_%d_", idExprSeq.Add(1)))
+
+	key := weak.Make(ident)
+	idExprRefs.Store(key, v)
+	// drop the ref when the ident is no longer used
+	runtime.AddCleanup(ident, func(key weak.Pointer[ast.Ident]) {
+		idExprRefs.Delete(key)
+	}, key)
+
+	return ident
+}
+
+var (
+	idExprSeq atomic.Int64
+	// idExprRefs side table of idents created by IDExpr to the referenced values
+	idExprRefs sync.Map
+)
+
+type nodeSnippet struct {
+	node ast.Node
+}
+
+func (n *nodeSnippet) IsNil() bool {
+	return n.node == nil
+}
+
+func (n *nodeSnippet) Frag(ctx context.Context) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		// placeholder names of idents created by IDExpr to the final names
+		oldnew := make([]string, 0)
+
+		ast.Inspect(n.node, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok {
+				if v, ok := idExprRefs.Load(weak.Make(ident)); ok {
+					b := &strings.Builder{}
+					for code := range Fragments(ctx, ID(v)) {
+						b.WriteString(code)
+					}
+					oldnew = append(oldnew, ident.Name, b.String())
+				}
+			}
+			return true
+		})
+
+		b := bytes.NewBuffer(nil)
+
+		cfg := &goprinter.Config{Mode: goprinter.UseSpaces | goprinter.TabIndent, Tabwidth: 8}
+		if err := cfg.Fprint(b, token.NewFileSet(), n.node); err != nil {
+			ReportError(ctx, fmt.Errorf("print node %T failed: %w", n.node, err))
+			return
+		}
+
+		code := b.String()
+		if len(oldnew) > 0 {
+			code = strings.NewReplacer(oldnew...).Replace(code)
+		}
+
+		if !yield(code) {
+			return
+		}
+	}
+}
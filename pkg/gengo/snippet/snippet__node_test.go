@@ -0,0 +1,62 @@
+package snippet
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/octohelm/gengo/pkg/gengo/internal"
+	"github.com/octohelm/gengo/pkg/namer"
+	testingx "github.com/octohelm/x/testing"
+)
+
+func TestNode(t *testing.T) {
+	tracker := namer.NewDefaultImportTracker()
+
+	ctx := internal.DumperContext.Inject(context.Background(), internal.NewDumper(namer.NewRawNamer("github.com/x/y", tracker)))
+
+	fn := &ast.FuncDecl{
+		Name: ast.NewIdent("NewBuffer"),
+		Type: &ast.FuncType{
+			Results: &ast.FieldList{
+				List: []*ast.Field{
+					{Type: &ast.StarExpr{X: IDExpr(reflect.TypeOf(bytes.Buffer{}))}},
+				},
+			},
+		},
+		Body: &ast.BlockStmt{
+			List: []ast.Stmt{
+				&ast.ReturnStmt{
+					Results: []ast.Expr{
+						&ast.CallExpr{
+							Fun:  ast.NewIdent("new"),
+							Args: []ast.Expr{IDExpr("bytes.Buffer")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// render twice to make sure node not modified
+	for range 2 {
+		b := &strings.Builder{}
+		for code := range Fragments(ctx, Node(fn)) {
+			b.WriteString(code)
+		}
+
+		testingx.Expect(t, b.String(), testingx.Be(`func NewBuffer() *bytes.Buffer {
+	return new(bytes.Buffer)
+}`))
+	}
+
+	ident := fn.Body.List[0].(*ast.ReturnStmt).Results[0].(*ast.CallExpr).Args[0].(*ast.Ident)
+	testingx.Expect(t, strings.HasPrefix(ident.Name, "_gengo_id_"), testingx.BeTrue())
+	testingx.Expect(t, ident.Obj == nil, testingx.BeTrue())
+	testingx.Expect(t, tracker.Imports(), testingx.Equal(map[string]string{
+		"bytes": "bytes",
+	}))
+}
@@ -3,16 +3,60 @@ package snippet
 import (
 	"context"
 	"iter"
+	"reflect"
 
 	"github.com/octohelm/gengo/pkg/gengo/internal"
+	gengotypes "github.com/octohelm/gengo/pkg/types"
 )
 
-func Value(v any) Snippet {
-	return &value{v: v}
+// ValueOption customizes how Value prints
+type ValueOption = internal.ValueLitOptFn
+
+// OnInterface prints value of interface type by fn,
+// default prints nil
+func OnInterface(fn func(v any) string) ValueOption {
+	return internal.OnInterface(fn)
+}
+
+// DynamicInterface prints dynamic value of interface type instead of nil,
+// untyped constant will be converted to keep the dynamic type, like float32(1.5)
+func DynamicInterface() ValueOption {
+	return internal.DynamicInterface(true)
+}
+
+// OnNamedType prints value of named type by fn when matched
+func OnNamedType(fn func(v any) (string, bool)) ValueOption {
+	return internal.OnNamedType(fn)
+}
+
+// OnNamedConstant prints value of named type as named constant when matched,
+// like time.Second instead of time.Duration(1000000000)
+func OnNamedConstant(fn func(v any) (gengotypes.TypeName, bool)) ValueOption {
+	return internal.OnNamedConstant(fn)
+}
+
+// NamedConstants prints value of named type as the registered named constant
+//
+//	snippet.Value(d, snippet.NamedConstants(map[any]gengotypes.TypeName{
+//		time.Second: gengotypes.Ref("time", "Second"),
+//	}))
+func NamedConstants(constants map[any]gengotypes.TypeName) ValueOption {
+	return OnNamedConstant(func(v any) (gengotypes.TypeName, bool) {
+		if !reflect.TypeOf(v).Comparable() {
+			return nil, false
+		}
+		ref, ok := constants[v]
+		return ref, ok
+	})
+}
+
+func Value(v any, opts ...ValueOption) Snippet {
+	return &value{v: v, opts: opts}
 }
 
 type value struct {
-	v any
+	v    any
+	opts []ValueOption
 }
 
 func (v *value) IsNil() bool {
@@ -23,7 +67,13 @@ func (v *value) Frag(ctx context.Context) iter.Seq[string] {
 	d := internal.DumperContext.From(ctx)
 
 	return func(yield func(string) bool) {
-		if !yield(d.ValueLit(v.v)) {
+		s, err := d.TryValueLit(v.v, v.opts...)
+		if err != nil {
+			ReportError(ctx, err)
+			return
+		}
+
+		if !yield(s) {
 			return
 		}
 	}
@@ -0,0 +1,241 @@
+package gengo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SourceMapMode controls how generated code maps back to the object which generator handled
+type SourceMapMode int
+
+const (
+	// SourceMapNone emits no source mapping
+	SourceMapNone SourceMapMode = iota
+	// SourceMapLineDirective emits `//line` directives,
+	// compile errors and panics in generated code will point at the originating file and line
+	SourceMapLineDirective
+	// SourceMapComment emits `// source:` comments
+	SourceMapComment
+)
+
+// SourceMap is the machine-readable source map written as `<generated file>.map`
+type SourceMap struct {
+	// File generated file name
+	File string `json:"file"`
+	// Generator name
+	Generator string `json:"generator"`
+	// Mappings generated line ranges to source positions
+	Mappings []SourceMapping `json:"mappings"`
+}
+
+type SourceMapping struct {
+	// GeneratedLine first line in generated file
+	GeneratedLine int `json:"generatedLine"`
+	// GeneratedEndLine last line in generated file
+	GeneratedEndLine int `json:"generatedEndLine"`
+	// Source file path relative to generated file
+	Source string `json:"source"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	// Kind of object, like type, func, const
+	Kind string `json:"kind"`
+	// Name of object
+	Name string `json:"name"`
+}
+
+const (
+	sourceMarkerPrefix = "//gengo:source:"
+	sourceMarkerEnd    = "end"
+)
+
+type sourceEntry struct {
+	pos  token.Position
+	kind string
+	name string
+}
+
+// markSource marks following rendered code generated for object at pos,
+// the marker is written lazily when code rendered
+func (ff *genfile) markSource(pos token.Position, kind string, name string) {
+	if ff.sourceMap == SourceMapNone || !pos.IsValid() {
+		return
+	}
+
+	ff.sources = append(ff.sources, sourceEntry{pos: pos, kind: kind, name: name})
+	ff.pendingSource = true
+}
+
+func (ff *genfile) flushSourceMarker() {
+	if !ff.pendingSource {
+		return
+	}
+	ff.pendingSource = false
+	ff.openSource = true
+
+	// blank line after marker to avoid joining doc comments of generated code
+	_, _ = fmt.Fprintf(ff.body, "\n%s%d\n\n", sourceMarkerPrefix, len(ff.sources)-1)
+}
+
+// endSource ends code generated for the object marked by markSource
+func (ff *genfile) endSource() {
+	ff.pendingSource = false
+
+	if !ff.openSource {
+		return
+	}
+	ff.openSource = false
+
+	_, _ = fmt.Fprintf(ff.body, "\n%s%s\n\n", sourceMarkerPrefix, sourceMarkerEnd)
+}
+
+// resolveSourceMap replaces source markers in formatted code with line directives or comments.
+//
+// Each mapping ends at the end marker, then line directive to the generated file will be emitted
+// to reset positions of following code.
+func (ff *genfile) resolveSourceMap(filename string, code []byte) ([]byte, *SourceMap) {
+	if ff.sourceMap == SourceMapNone {
+		return code, nil
+	}
+
+	sm := &SourceMap{
+		File:      filepath.Base(filename),
+		Generator: ff.name,
+		Mappings:  make([]SourceMapping, 0),
+	}
+
+	lines := strings.Split(string(code), "\n")
+	out := make([]string, 0, len(lines))
+
+	// markerAt returns index of source entry, or -1 for end marker
+	markerAt := func(i int) (int, bool) {
+		idx, found := strings.CutPrefix(strings.TrimSpace(lines[i]), sourceMarkerPrefix)
+		if !found {
+			return 0, false
+		}
+		if idx == sourceMarkerEnd {
+			return -1, true
+		}
+		n, err := strconv.Atoi(idx)
+		if err != nil || n < 0 || n >= len(ff.sources) {
+			return 0, false
+		}
+		return n, true
+	}
+
+	opened := false
+
+	closeMapping := func() {
+		if !opened {
+			return
+		}
+		opened = false
+
+		m := &sm.Mappings[len(sm.Mappings)-1]
+
+		end := len(out)
+		// trim trailing blank lines
+		for end > m.GeneratedLine && strings.TrimSpace(out[end-1]) == "" {
+			end--
+		}
+		m.GeneratedEndLine = end
+	}
+
+	for i := 0; i < len(lines); i++ {
+		n, ok := markerAt(i)
+		if !ok {
+			out = append(out, lines[i])
+			continue
+		}
+
+		// skip blank line after marker
+		if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) == "" {
+			i++
+		}
+
+		if n < 0 {
+			if !opened {
+				continue
+			}
+
+			closeMapping()
+
+			if ff.sourceMap == SourceMapLineDirective {
+				// the line after directive is the next line of generated file
+				out = append(out, fmt.Sprintf("//line %s:%d", sm.File, len(out)+2))
+			}
+			continue
+		}
+
+		// drop marker without code followed
+		next := i + 1
+		for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+			next++
+		}
+		if next >= len(lines) {
+			continue
+		}
+		if _, ok := markerAt(next); ok {
+			continue
+		}
+
+		closeMapping()
+
+		e := ff.sources[n]
+
+		source := e.pos.Filename
+		if rel, err := filepath.Rel(filepath.Dir(filename), source); err == nil {
+			source = filepath.ToSlash(rel)
+		}
+
+		switch ff.sourceMap {
+		case SourceMapLineDirective:
+			out = append(out, fmt.Sprintf("//line %s:%d", source, e.pos.Line))
+		default:
+			out = append(out, fmt.Sprintf("// source: %s:%d %s %s", source, e.pos.Line, e.kind, e.name))
+		}
+
+		sm.Mappings = append(sm.Mappings, SourceMapping{
+			GeneratedLine: len(out) + 1,
+			Source:        source,
+			Line:          e.pos.Line,
+			Column:        e.pos.Column,
+			Kind:          e.kind,
+			Name:          e.name,
+		})
+		opened = true
+	}
+
+	closeMapping()
+
+	return []byte(strings.Join(out, "\n")), sm
+}
+
+// writeSourceMap writes sm as `<filename>.map`,
+// previous one will be removed when sm is nil
+func writeSourceMap(filename string, sm *SourceMap) error {
+	if sm == nil {
+		return removeSourceMap(filename)
+	}
+
+	b := bytes.NewBuffer(nil)
+	e := json.NewEncoder(b)
+	e.SetIndent("", "  ")
+	if err := e.Encode(sm); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename+".map", b.Bytes(), 0o666)
+}
+
+func removeSourceMap(filename string) error {
+	if err := os.Remove(filename + ".map"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
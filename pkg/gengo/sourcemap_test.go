@@ -0,0 +1,92 @@
+package gengo_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	testingx "github.com/octohelm/x/testing"
+)
+
+func newSourceMapGen() *stubGen {
+	g := newFormattedGen()
+	g.name = "sourcemap"
+	g.endPackage = func(g *stubGen, c gengo.Context) error {
+		c.RenderT(`
+func endPackage() {}
+`)
+		return nil
+	}
+	return g
+}
+
+var sourceMapGenerateCases = []generateCase{
+	{
+		name: "source map with line directive",
+		args: gengo.GeneratorArgs{
+			Globals: map[string][]string{
+				"gengo:sourcemap": {""},
+			},
+			OutputFileBaseName: "zz_generated_sourcemap",
+			Force:              true,
+			TypeCheck:          true,
+			SourceMap:          gengo.SourceMapLineDirective,
+		},
+		gen: newSourceMapGen(),
+		expect: func(t *testing.T, r *generateRun, err error) {
+			testingx.Expect(t, err, testingx.BeNil[error]())
+
+			filename := "zz_generated_sourcemap.sourcemap.go"
+
+			data, err := os.ReadFile(filename)
+			testingx.Expect(t, err, testingx.BeNil[error]())
+
+			mapData, err := os.ReadFile(filename + ".map")
+			testingx.Expect(t, err, testingx.BeNil[error]())
+
+			sm := &gengo.SourceMap{}
+			testingx.Expect(t, json.Unmarshal(mapData, sm), testingx.BeNil[error]())
+			testingx.Expect(t, sm.Mappings, testingx.HaveLen[[]gengo.SourceMapping](1))
+
+			m := sm.Mappings[0]
+			testingx.Expect(t, m.Source, testingx.Be("b.go"))
+			testingx.Expect(t, m.Name, testingx.Be("Obj"))
+			// mapping ends at the end of code generated for the object
+			testingx.Expect(t, m.GeneratedEndLine, testingx.Be(m.GeneratedLine))
+
+			lines := strings.Split(string(data), "\n")
+			testingx.Expect(t, lines[m.GeneratedLine-2], testingx.Be(fmt.Sprintf("//line b.go:%d", m.Line)))
+			testingx.Expect(t, lines[m.GeneratedLine-1], testingx.Be("func (v *Obj) Formatted() {}"))
+			// positions reset to generated file after mapped code
+			testingx.Expect(t, strings.TrimSpace(lines[m.GeneratedEndLine]), testingx.Be(""))
+			testingx.Expect(t, lines[m.GeneratedEndLine+1], testingx.Be(fmt.Sprintf("//line %s:%d", filename, m.GeneratedEndLine+3)))
+
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, filename, data, parser.SkipObjectResolution)
+			testingx.Expect(t, err, testingx.BeNil[error]())
+
+			for _, decl := range f.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "endPackage" {
+					pos := fset.Position(fn.Pos())
+					testingx.Expect(t, filepath.Base(pos.Filename), testingx.Be(filename))
+					testingx.Expect(t, pos.Line, testingx.Be(fset.PositionFor(fn.Pos(), false).Line))
+				}
+			}
+
+			// stale source map should be removed
+			r.args.SourceMap = gengo.SourceMapNone
+
+			testingx.Expect(t, r.Execute(), testingx.BeNil[error]())
+
+			_, err = os.Stat(filename + ".map")
+			testingx.Expect(t, os.IsNotExist(err), testingx.BeTrue())
+		},
+	},
+}
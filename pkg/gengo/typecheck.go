@@ -0,0 +1,115 @@
+package gengo
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// TypeCheckError reports type errors of generated file
+type TypeCheckError struct {
+	// Filename of generated file
+	Filename string
+	// Generator name
+	Generator string
+	// Errors with positions in generated file
+	Errors []types.Error
+}
+
+func (e *TypeCheckError) Error() string {
+	b := &strings.Builder{}
+
+	_, _ = fmt.Fprintf(b, "gengo:%s generated %s with type errors:", e.Generator, filepath.Base(e.Filename))
+
+	for _, err := range e.Errors {
+		_, _ = fmt.Fprintf(b, "\n\t%s", err)
+	}
+
+	return b.String()
+}
+
+// typeCheck checks generated code together with other files of the package,
+// imports are resolved from loaded packages first.
+// Files are parsed into a private FileSet, and only errors in the generated file will be reported.
+func typeCheck(c Context, generator string, filename string, src []byte) error {
+	pkg := c.Package("")
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+
+	generated := fset.File(file.FileStart)
+	files := []*ast.File{file}
+
+	for _, f := range pkg.Files() {
+		name := pkg.FileSet().File(f.FileStart).Name()
+
+		// skip the previous version of generated file
+		if name == filename {
+			continue
+		}
+
+		other, err := parser.ParseFile(fset, name, nil, parser.SkipObjectResolution)
+		if err != nil {
+			// removed after loaded, like stale generated files
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+
+		files = append(files, other)
+	}
+
+	e := &TypeCheckError{
+		Filename:  filename,
+		Generator: generator,
+	}
+
+	conf := &types.Config{
+		Importer: &loadedImporter{
+			c:        c,
+			fallback: importer.ForCompiler(fset, "source", nil).(types.ImporterFrom),
+		},
+		Error: func(err error) {
+			var terr types.Error
+			// match by token.File, filename of position could be changed by //line directives
+			if errors.As(err, &terr) && terr.Fset.File(terr.Pos) == generated {
+				e.Errors = append(e.Errors, terr)
+			}
+		},
+	}
+
+	_, _ = conf.Check(pkg.Pkg().Path(), fset, files, nil)
+
+	if len(e.Errors) > 0 {
+		return e
+	}
+
+	return nil
+}
+
+type loadedImporter struct {
+	c        Context
+	fallback types.ImporterFrom
+}
+
+func (i *loadedImporter) Import(path string) (*types.Package, error) {
+	return i.ImportFrom(path, "", 0)
+}
+
+func (i *loadedImporter) ImportFrom(path string, dir string, mode types.ImportMode) (*types.Package, error) {
+	if p := i.c.Package(path); p != nil {
+		return p.Pkg(), nil
+	}
+	return i.fallback.ImportFrom(path, dir, mode)
+}
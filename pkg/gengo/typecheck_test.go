@@ -0,0 +1,90 @@
+package gengo_test
+
+import (
+	"errors"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	testingx "github.com/octohelm/x/testing"
+)
+
+func newBrokenGen() *stubGen {
+	return &stubGen{
+		name: "broken",
+		generateType: renderForObj(`
+func (v *@Type) Broken() string {
+	return v.NotExists
+}
+`),
+	}
+}
+
+func expectTypeCheckError(t *testing.T, r *generateRun, err error) {
+	tcErr := &gengo.TypeCheckError{}
+	testingx.Expect(t, errors.As(err, &tcErr), testingx.BeTrue())
+	testingx.Expect(t, tcErr.Generator, testingx.Be("broken"))
+	testingx.Expect(t, tcErr.Errors, testingx.HaveLen[[]types.Error](1))
+
+	_, err = os.Stat("zz_generated_typecheck.broken.go")
+	testingx.Expect(t, os.IsNotExist(err), testingx.BeTrue())
+}
+
+var typeCheckGenerateCases = []generateCase{
+	{
+		name: "type check",
+		args: gengo.GeneratorArgs{
+			Globals: map[string][]string{
+				"gengo:broken": {""},
+			},
+			OutputFileBaseName: "zz_generated_typecheck",
+			Force:              true,
+			TypeCheck:          true,
+		},
+		gen:    newBrokenGen(),
+		expect: expectTypeCheckError,
+	},
+	{
+		name: "type check with line directive",
+		args: gengo.GeneratorArgs{
+			Globals: map[string][]string{
+				"gengo:broken": {""},
+			},
+			OutputFileBaseName: "zz_generated_typecheck",
+			Force:              true,
+			TypeCheck:          true,
+			SourceMap:          gengo.SourceMapLineDirective,
+		},
+		gen:    newBrokenGen(),
+		expect: expectTypeCheckError,
+	},
+	{
+		name: "type check only generated file",
+		args: gengo.GeneratorArgs{
+			Globals: map[string][]string{
+				"gengo:formatted": {""},
+			},
+			OutputFileBaseName: "zz_generated",
+			Force:              true,
+			TypeCheck:          true,
+		},
+		prepares: []func(dir string) error{
+			func(dir string) error {
+				// type errors of other files should not be reported for generated file
+				return os.WriteFile(filepath.Join(dir, "broken.go"), []byte("package b\n\nvar _ int = \"x\"\n"), 0o644)
+			},
+		},
+		gen: newFormattedGen(),
+		expect: func(t *testing.T, r *generateRun, err error) {
+			testingx.Expect(t, err, testingx.BeNil[error]())
+
+			_, err = os.Stat("zz_generated.formatted.go")
+			testingx.Expect(t, err, testingx.BeNil[error]())
+
+			// previous generated files of the package are removed in the first run
+			testingx.Expect(t, r.Execute(), testingx.BeNil[error]())
+		},
+	},
+}
@@ -0,0 +1,160 @@
+package gengo
+
+import (
+	corecontext "context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/go-courier/logr"
+)
+
+func (c *gengoCtx) universeExecute(pctx corecontext.Context, generators ...Generator) (finalErr error) {
+	outputPkgPath := c.universeOutputPkgPath()
+	pkgPaths := make([]string, 0)
+
+	for pkgPath, direct := range c.universe.LocalPkgPaths() {
+		if !c.args.All && !direct {
+			continue
+		}
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+
+	output := c.universe.Package(outputPkgPath)
+	if output == nil {
+		return fmt.Errorf("invalid universe output pkg `%s`", outputPkgPath)
+	}
+
+	ctx, l := logr.FromContext(pctx).Start(pctx, "generate universe", slog.String("scope", outputPkgPath))
+	defer l.End()
+
+	defer func() {
+		if finalErr != nil {
+			l.Error(finalErr)
+		}
+	}()
+
+	for _, registered := range generators {
+		// new instance for each run, to avoid state accumulated across Execute calls
+		g := c.New(registered)
+
+		ug, ok := g.(UniverseGenerator)
+		if !ok {
+			return fmt.Errorf("`%s` new instance is not an UniverseGenerator", registered.Name())
+		}
+
+		for _, pkgPath := range pkgPaths {
+			// rendered codes will be dropped, only for collecting
+			collectCtx, err := c.newGeneratorCtx(pkgPath, g)
+			if err != nil {
+				return err
+			}
+
+			collectCtx.l = l.WithValues("gengo", g.Name())
+
+			if err := collectCtx.doGenerate(ctx, g); err != nil {
+				return fmt.Errorf("`%s` collect failed for %s: %w", g.Name(), pkgPath, err)
+			}
+
+			if err := collectCtx.Err(); err != nil {
+				return fmt.Errorf("`%s` collect failed for %s: %w", g.Name(), pkgPath, err)
+			}
+		}
+
+		outputCtx, err := c.newGeneratorCtx(outputPkgPath, g)
+		if err != nil {
+			return err
+		}
+
+		outputCtx.l = l.WithValues("gengo", g.Name())
+
+		if err := ug.GenerateUniverse(outputCtx); err != nil {
+			if errors.Is(err, ErrSkip) {
+				continue
+			}
+			if !errors.Is(err, ErrIgnore) {
+				return fmt.Errorf("`%s` generate universe failed for %s: %w", g.Name(), outputPkgPath, err)
+			}
+			outputCtx.ignore = true
+		}
+
+		for _, fn := range outputCtx.defers {
+			if err := fn(outputCtx); err != nil {
+				return fmt.Errorf("`%s` defer generate universe failed for %s: %w", g.Name(), outputPkgPath, err)
+			}
+		}
+
+		if err := outputCtx.Err(); err != nil {
+			return fmt.Errorf("`%s` render universe failed for %s: %w", g.Name(), outputPkgPath, err)
+		}
+
+		if outputCtx.IsZero() {
+			// remove previous generated
+			filename := filepath.Join(output.SourceDir(), outputCtx.genfile.Filename(c.args))
+			if err := os.RemoveAll(filename); err != nil {
+				return err
+			}
+			if err := removeSourceMap(filename); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := outputCtx.genfile.WriteToFile(outputCtx, c.args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *gengoCtx) newGeneratorCtx(pkgPath string, g Generator) (*gengoCtx, error) {
+	p := c.universe.Package(pkgPath)
+	if p == nil {
+		return nil, fmt.Errorf("invalid pkg `%s`", pkgPath)
+	}
+
+	genCtx := &gengoCtx{
+		args:     c.args,
+		universe: c.universe,
+		pkg:      p,
+		pkgTags:  pkgTagsOf(p),
+		genfile:  newGenfile(g.Name(), c.args.ImportNaming),
+		gen:      g,
+	}
+
+	if err := genCtx.genfile.InitWith(genCtx); err != nil {
+		return nil, err
+	}
+
+	genCtx.genfile.configure(c.args, g)
+
+	return genCtx, nil
+}
+
+func (c *gengoCtx) universeOutputPkgPath() string {
+	if c.args.UniverseOutputPackage != "" {
+		return c.args.UniverseOutputPackage
+	}
+	// local pkg paths are sorted, the first entrypoint package in import path order is picked
+	for pkgPath, direct := range c.universe.LocalPkgPaths() {
+		if direct {
+			return pkgPath
+		}
+	}
+	return ""
+}
+
+// universeFilenamesOf returns filenames which generators will write into the universe output package,
+// to keep them from stale cleanup when generating for the package.
+func (c *gengoCtx) universeFilenamesOf(generators ...Generator) map[string][]string {
+	filenames := make([]string, 0, len(generators))
+	for _, g := range generators {
+		filenames = append(filenames, newGenfile(g.Name(), c.args.ImportNaming).Filename(c.args))
+	}
+	return map[string][]string{
+		c.universeOutputPkgPath(): filenames,
+	}
+}
@@ -0,0 +1,97 @@
+package gengo_test
+
+import (
+	"go/types"
+	"os"
+	"testing"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	"github.com/octohelm/gengo/pkg/gengo/snippet"
+	testingx "github.com/octohelm/x/testing"
+)
+
+// universeStubGen is stubGen as UniverseGenerator
+type universeStubGen struct {
+	stubGen
+
+	generateUniverse func(g *stubGen, c gengo.Context) error
+}
+
+var _ gengo.UniverseGenerator = &universeStubGen{}
+
+func (g *universeStubGen) New(c gengo.Context) gengo.Generator {
+	n := *g
+	n.collected = nil
+	return &n
+}
+
+func (g *universeStubGen) GenerateUniverse(c gengo.Context) error {
+	return g.generateUniverse(&g.stubGen, c)
+}
+
+func newRegistryGen(generateUniverse func(g *stubGen, c gengo.Context) error) *universeStubGen {
+	return &universeStubGen{
+		stubGen: stubGen{
+			name: "registry",
+			generateType: func(g *stubGen, c gengo.Context, named *types.Named) error {
+				g.collected = append(g.collected, named.Obj().Pkg().Path()+"."+named.Obj().Name())
+				return nil
+			},
+		},
+		generateUniverse: generateUniverse,
+	}
+}
+
+var universeGenerateCases = []generateCase{
+	{
+		name: "generate universe",
+		args: gengo.GeneratorArgs{
+			Globals: map[string][]string{
+				"gengo:registry": {""},
+			},
+			OutputFileBaseName: "zz_generated_universe",
+		},
+		gen: newRegistryGen(func(g *stubGen, c gengo.Context) error {
+			c.RenderT(`
+func RegisteredTypeNames() []string {
+	return @typeNames
+}
+`, snippet.ValueArg("typeNames", g.collected))
+
+			return nil
+		}),
+		expect: func(t *testing.T, r *generateRun, err error) {
+			testingx.Expect(t, err, testingx.BeNil[error]())
+
+			// state should not be accumulated across runs
+			testingx.Expect(t, r.Execute(), testingx.BeNil[error]())
+
+			// reload to include the generated universe output in package files,
+			// which should not be cleaned up as stale file of the package
+			r.Reload()
+
+			testingx.Expect(t, r.ExecuteWith(newRegistryGen(func(g *stubGen, c gengo.Context) error {
+				return gengo.ErrIgnore
+			})), testingx.BeNil[error]())
+
+			data, err := os.ReadFile("zz_generated_universe.registry.go")
+			testingx.Expect(t, err, testingx.BeNil[error]())
+			testingx.Expect(t, string(data), testingx.Equal(`/*
+Package b GENERATED BY gengo:registry
+DON'T EDIT THIS FILE
+*/
+package b
+
+func RegisteredTypeNames() []string {
+	return []string{
+		"github.com/octohelm/gengo/testdata/a/b.B",
+		"github.com/octohelm/gengo/testdata/a/b.List",
+		"github.com/octohelm/gengo/testdata/a/b.Obj",
+		"github.com/octohelm/gengo/testdata/a/b.SubObj",
+		"github.com/octohelm/gengo/testdata/a/b.Third",
+	}
+}
+`))
+		},
+	},
+}
@@ -0,0 +1,105 @@
+package namer
+
+import (
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ImportNaming resolves candidate local names for import path.
+// ImportTracker picks the first one which is free.
+type ImportNaming interface {
+	LocalNames(path string) iter.Seq[string]
+}
+
+// ImportNamingFunc adapts func as ImportNaming
+type ImportNamingFunc func(path string) iter.Seq[string]
+
+func (fn ImportNamingFunc) LocalNames(path string) iter.Seq[string] {
+	return fn(path)
+}
+
+// DefaultImportNaming joins last path segments until free,
+// with special case for `domain` and `apis` segments and `vN` suffix
+var DefaultImportNaming ImportNaming = ImportNamingFunc(func(path string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		parts := strings.Split(path, "/")
+
+		for i := range len(parts) {
+			if !yield(golangTrackerLocalName(parts, i+1)) {
+				return
+			}
+		}
+	}
+})
+
+// LastSegmentImportNaming uses last path segment,
+// with numeric suffix when taken, like `v1`, `v11`, `v12`
+var LastSegmentImportNaming ImportNaming = ImportNamingFunc(func(path string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		localName := toLocalName(path[strings.LastIndex(path, "/")+1:])
+
+		if !yield(localName) {
+			return
+		}
+
+		for i := 1; ; i++ {
+			if !yield(localName + strconv.Itoa(i)) {
+				return
+			}
+		}
+	}
+})
+
+// ImportNamingMapping uses alias of the longest matched path prefix,
+// the rest path segments will be joined after the alias.
+// When no prefix matched or all taken, fallback will be used, default is DefaultImportNaming.
+//
+//	ImportNamingMapping(map[string]string{
+//		"k8s.io/apimachinery/pkg/apis/meta/v1": "metav1",
+//		"github.com/org/apis": "orgapis",	// github.com/org/apis/user/v1 => orgapisuserv1
+//	}, nil)
+func ImportNamingMapping(prefixToAlias map[string]string, fallback ImportNaming) ImportNaming {
+	if fallback == nil {
+		fallback = DefaultImportNaming
+	}
+
+	prefixes := make([]string, 0, len(prefixToAlias))
+	for prefix := range prefixToAlias {
+		prefixes = append(prefixes, prefix)
+	}
+	// longest first
+	slices.SortFunc(prefixes, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(b) - len(a)
+		}
+		return strings.Compare(a, b)
+	})
+
+	return ImportNamingFunc(func(path string) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			for _, prefix := range prefixes {
+				if path == prefix {
+					if !yield(prefixToAlias[prefix]) {
+						return
+					}
+					break
+				}
+
+				if rest, ok := strings.CutPrefix(path, prefix+"/"); ok {
+					if !yield(prefixToAlias[prefix] + toLocalName(strings.Split(rest, "/")...)) {
+						return
+					}
+					break
+				}
+			}
+
+			for localName := range fallback.LocalNames(path) {
+				if !yield(localName) {
+					return
+				}
+			}
+		}
+	})
+}
@@ -18,16 +18,50 @@ type ImportTracker interface {
 	Imports() map[string]string
 }
 
+// ImportReserver could be implemented by ImportTracker
+type ImportReserver interface {
+	// Reserve local names which could not be used as import alias,
+	// like top-level identifiers declared in the target package
+	Reserve(localNames ...string)
+}
+
+// ImportPreferrer could be implemented by ImportTracker
+type ImportPreferrer interface {
+	// Prefer local name for package path, which will be used when it is free
+	Prefer(packagePath string, localName string)
+}
+
+var (
+	_ ImportReserver  = &defaultImportTracker{}
+	_ ImportPreferrer = &defaultImportTracker{}
+)
+
 type defaultImportTracker struct {
 	pathToName map[string]string
 	nameToPath map[string]string
+	reserved   map[string]bool
+	preferred  map[string]string
+	naming     ImportNaming
 	checkStd   bool
 }
 
 func NewDefaultImportTracker() ImportTracker {
+	return NewImportTracker(DefaultImportNaming)
+}
+
+// NewImportTracker creates ImportTracker with naming strategy,
+// DefaultImportNaming will be used when naming is nil
+func NewImportTracker(naming ImportNaming) ImportTracker {
+	if naming == nil {
+		naming = DefaultImportNaming
+	}
+
 	return &defaultImportTracker{
 		pathToName: map[string]string{},
 		nameToPath: map[string]string{},
+		reserved:   map[string]bool{},
+		preferred:  map[string]string{},
+		naming:     naming,
 		checkStd:   true,
 	}
 }
@@ -36,30 +70,79 @@ func (tracker *defaultImportTracker) AddType(o gengotypes.TypeName) {
 	tracker.add(o.Pkg().Path())
 }
 
+func (tracker *defaultImportTracker) Reserve(localNames ...string) {
+	for _, name := range localNames {
+		tracker.reserved[name] = true
+	}
+}
+
+func (tracker *defaultImportTracker) Prefer(path string, localName string) {
+	if _, ok := tracker.preferred[path]; !ok {
+		tracker.preferred[path] = localName
+	}
+}
+
 func (tracker *defaultImportTracker) add(path string) {
 	if _, ok := tracker.pathToName[path]; ok {
 		return
 	}
 
-	parts := strings.Split(path, "/")
+	if localName, ok := tracker.preferred[path]; ok && tracker.available(path, localName) {
+		tracker.use(path, localName)
+		return
+	}
 
-	for i := range len(parts) {
-		localName := golangTrackerLocalName(parts, i+1)
+	naming := tracker.naming
+	if naming == nil {
+		naming = DefaultImportNaming
+	}
 
-		if tracker.checkStd {
-			if p, ok := std.nameToPath[localName]; ok && p != path {
-				continue
-			}
+	first := ""
+
+	for localName := range naming.LocalNames(path) {
+		if first == "" {
+			first = localName
 		}
 
-		if _, ok := tracker.nameToPath[localName]; !ok {
-			tracker.nameToPath[localName] = path
-			tracker.pathToName[path] = localName
-			break
+		if tracker.available(path, localName) {
+			tracker.use(path, localName)
+			return
+		}
+	}
+
+	if first == "" {
+		first = toLocalName(path[strings.LastIndex(path, "/")+1:])
+	}
+
+	// all candidates taken, fallback to numeric suffix
+	for i := 1; ; i++ {
+		if localName := first + strconv.Itoa(i); tracker.available(path, localName) {
+			tracker.use(path, localName)
+			return
 		}
 	}
 }
 
+func (tracker *defaultImportTracker) available(path string, localName string) bool {
+	if tracker.reserved[localName] {
+		return false
+	}
+
+	if tracker.checkStd {
+		if p, ok := std.nameToPath[localName]; ok && p != path {
+			return false
+		}
+	}
+
+	_, ok := tracker.nameToPath[localName]
+	return !ok
+}
+
+func (tracker *defaultImportTracker) use(path string, localName string) {
+	tracker.nameToPath[localName] = path
+	tracker.pathToName[path] = localName
+}
+
 func toLocalName(parts ...string) string {
 	return strings.ToLower(camelcase.LowerCamelCase(strings.Join(parts, "")))
 }
@@ -0,0 +1,125 @@
+package namer
+
+import (
+	"strings"
+	"testing"
+
+	gengotypes "github.com/octohelm/gengo/pkg/types"
+	testingx "github.com/octohelm/x/testing"
+)
+
+func TestDefaultImportTracker(t *testing.T) {
+	t.Run("avoid reserved names", func(t *testing.T) {
+		tracker := NewDefaultImportTracker().(*defaultImportTracker)
+		tracker.Reserve("b", "time")
+
+		tracker.AddType(gengotypes.Ref("github.com/x/a/b", "T"))
+		tracker.AddType(gengotypes.Ref("github.com/x/time", "T"))
+
+		testingx.Expect(t, tracker.Imports(), testingx.Equal(map[string]string{
+			"github.com/x/a/b":  "ab",
+			"github.com/x/time": "xtime",
+		}))
+	})
+
+	t.Run("fallback to numeric suffix", func(t *testing.T) {
+		tracker := NewDefaultImportTracker().(*defaultImportTracker)
+		tracker.Reserve("v1")
+
+		tracker.AddType(gengotypes.Ref("v1", "T"))
+
+		testingx.Expect(t, tracker.LocalNameOf("v1"), testingx.Be("v11"))
+	})
+
+	t.Run("prefer existing alias when free", func(t *testing.T) {
+		tracker := NewDefaultImportTracker().(*defaultImportTracker)
+		tracker.Reserve("corev1")
+		tracker.Prefer("k8s.io/apimachinery/pkg/apis/meta/v1", "metav1")
+		tracker.Prefer("k8s.io/api/core/v1", "corev1")
+
+		tracker.AddType(gengotypes.Ref("k8s.io/apimachinery/pkg/apis/meta/v1", "ObjectMeta"))
+		tracker.AddType(gengotypes.Ref("k8s.io/api/core/v1", "Pod"))
+
+		testingx.Expect(t, tracker.Imports(), testingx.Equal(map[string]string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1": "metav1",
+			"k8s.io/api/core/v1":                   "apicorev1",
+		}))
+	})
+
+	t.Run("last segment naming", func(t *testing.T) {
+		tracker := NewImportTracker(LastSegmentImportNaming)
+
+		tracker.AddType(gengotypes.Ref("k8s.io/apimachinery/pkg/apis/meta/v1", "ObjectMeta"))
+		tracker.AddType(gengotypes.Ref("k8s.io/api/core/v1", "Pod"))
+		tracker.AddType(gengotypes.Ref("github.com/x/apis/v1", "T"))
+
+		testingx.Expect(t, tracker.Imports(), testingx.Equal(map[string]string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1": "v1",
+			"k8s.io/api/core/v1":                   "v11",
+			"github.com/x/apis/v1":                 "v12",
+		}))
+	})
+
+	t.Run("mapping naming", func(t *testing.T) {
+		tracker := NewImportTracker(ImportNamingMapping(map[string]string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1": "metav1",
+			"github.com/org/apis":                  "org",
+		}, LastSegmentImportNaming))
+
+		tracker.AddType(gengotypes.Ref("k8s.io/apimachinery/pkg/apis/meta/v1", "ObjectMeta"))
+		tracker.AddType(gengotypes.Ref("github.com/org/apis/user/v1", "User"))
+		tracker.AddType(gengotypes.Ref("github.com/org/apisx/v1", "T"))
+
+		testingx.Expect(t, tracker.Imports(), testingx.Equal(map[string]string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1": "metav1",
+			"github.com/org/apis/user/v1":          "orguserv1",
+			"github.com/org/apisx/v1":              "v1",
+		}))
+	})
+
+	t.Run("stable aliases independent of render order", func(t *testing.T) {
+		render := func(paths ...string) (string, map[string]string) {
+			tracker := NewStableImportTracker(NewDefaultImportTracker())
+			n := NewRawNamer("github.com/x/y", tracker)
+
+			b := &strings.Builder{}
+			for _, p := range paths {
+				b.WriteString(n.Name(gengotypes.Ref(p, "T")))
+				b.WriteString(";")
+			}
+
+			return string(tracker.Replace([]byte(b.String()))), tracker.Imports()
+		}
+
+		code, imports := render("k8s.io/api/core/v1", "k8s.io/apimachinery/pkg/apis/meta/v1")
+		code2, imports2 := render("k8s.io/apimachinery/pkg/apis/meta/v1", "k8s.io/api/core/v1")
+
+		testingx.Expect(t, imports, testingx.Equal(imports2))
+		testingx.Expect(t, imports, testingx.Equal(map[string]string{
+			"k8s.io/api/core/v1":                   "corev1",
+			"k8s.io/apimachinery/pkg/apis/meta/v1": "metav1",
+		}))
+		testingx.Expect(t, code, testingx.Be("corev1.T;metav1.T;"))
+		testingx.Expect(t, code2, testingx.Be("metav1.T;corev1.T;"))
+
+		_, imports = render("github.com/b/x/v1", "github.com/a/x/v1")
+		testingx.Expect(t, imports, testingx.Equal(map[string]string{
+			"github.com/a/x/v1": "xv1",
+			"github.com/b/x/v1": "bxv1",
+		}))
+	})
+
+	t.Run("stable with tracker without reserving and preferring", func(t *testing.T) {
+		tracker := NewStableImportTracker(struct{ ImportTracker }{NewDefaultImportTracker()})
+		tracker.Reserve("v1")
+		tracker.Prefer("k8s.io/api/core/v1", "corev1")
+
+		tracker.AddType(gengotypes.Ref("k8s.io/api/core/v1", "Pod"))
+		tracker.AddType(gengotypes.Ref("v1", "T"))
+
+		testingx.Expect(t, tracker.Imports(), testingx.Equal(map[string]string{
+			"k8s.io/api/core/v1": "corev1",
+			"v1":                 "v1",
+		}))
+	})
+}
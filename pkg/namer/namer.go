@@ -67,6 +67,11 @@ func (n *rawNamer) Name(typeName gengotypes.TypeName) string {
 	}
 }
 
+// PkgPath of the package which names are used in
+func (n *rawNamer) PkgPath() string {
+	return n.pkgPath
+}
+
 func (n *rawNamer) processName(name string) string {
 	t, err := gengotypes.ParseTypeRef(name)
 	if err != nil {
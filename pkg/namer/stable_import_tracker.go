@@ -0,0 +1,122 @@
+package namer
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	gengotypes "github.com/octohelm/gengo/pkg/types"
+)
+
+// StableImportTracker defers local name assignment until all import paths collected,
+// to make aliases independent of render order.
+//
+// LocalNameOf returns placeholder before Resolve,
+// which should be replaced by Replace after rendering.
+type StableImportTracker interface {
+	ImportTracker
+	ImportReserver
+	ImportPreferrer
+	// Resolve assigns local names for all collected paths,
+	// preferred paths first, then others in path order
+	Resolve()
+	// Replace placeholders in rendered code with final local names
+	Replace(code []byte) []byte
+}
+
+func NewStableImportTracker(tracker ImportTracker) StableImportTracker {
+	return &stableImportTracker{
+		ImportTracker: tracker,
+		placeholders:  map[string]string{},
+		preferred:     map[string]bool{},
+	}
+}
+
+type stableImportTracker struct {
+	ImportTracker
+
+	// path to placeholder
+	placeholders map[string]string
+	preferred    map[string]bool
+	resolved     bool
+}
+
+func (tracker *stableImportTracker) AddType(o gengotypes.TypeName) {
+	if tracker.resolved {
+		tracker.ImportTracker.AddType(o)
+		return
+	}
+
+	path := o.Pkg().Path()
+
+	if _, ok := tracker.placeholders[path]; !ok {
+		tracker.placeholders[path] = "_gengo_import_" + strconv.Itoa(len(tracker.placeholders)) + "_"
+	}
+}
+
+func (tracker *stableImportTracker) Reserve(localNames ...string) {
+	if r, ok := tracker.ImportTracker.(ImportReserver); ok {
+		r.Reserve(localNames...)
+	}
+}
+
+func (tracker *stableImportTracker) Prefer(path string, localName string) {
+	tracker.preferred[path] = true
+	if p, ok := tracker.ImportTracker.(ImportPreferrer); ok {
+		p.Prefer(path, localName)
+	}
+}
+
+func (tracker *stableImportTracker) LocalNameOf(path string) string {
+	if tracker.resolved {
+		return tracker.ImportTracker.LocalNameOf(path)
+	}
+	return tracker.placeholders[path]
+}
+
+func (tracker *stableImportTracker) Imports() map[string]string {
+	tracker.Resolve()
+
+	return tracker.ImportTracker.Imports()
+}
+
+func (tracker *stableImportTracker) Resolve() {
+	if tracker.resolved {
+		return
+	}
+	tracker.resolved = true
+
+	paths := make([]string, 0, len(tracker.placeholders))
+	for path := range tracker.placeholders {
+		paths = append(paths, path)
+	}
+
+	slices.SortFunc(paths, func(a, b string) int {
+		if tracker.preferred[a] != tracker.preferred[b] {
+			if tracker.preferred[a] {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	for _, path := range paths {
+		tracker.ImportTracker.AddType(gengotypes.Ref(path, ""))
+	}
+}
+
+func (tracker *stableImportTracker) Replace(code []byte) []byte {
+	tracker.Resolve()
+
+	if len(tracker.placeholders) == 0 {
+		return code
+	}
+
+	oldnew := make([]string, 0, len(tracker.placeholders)*2)
+	for path, placeholder := range tracker.placeholders {
+		oldnew = append(oldnew, placeholder, tracker.ImportTracker.LocalNameOf(path))
+	}
+
+	return []byte(strings.NewReplacer(oldnew...).Replace(string(code)))
+}
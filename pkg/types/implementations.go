@@ -0,0 +1,125 @@
+package types
+
+import (
+	"cmp"
+	"fmt"
+	"go/types"
+	"slices"
+	"sync"
+)
+
+// Implementation is named type which implements interface
+type Implementation struct {
+	Type *types.TypeName
+	// Pointer is true when only pointer of the type implements the interface
+	Pointer bool
+}
+
+type implementationIndex struct {
+	mu    sync.Mutex
+	cache map[string][]Implementation
+}
+
+// Implementations returns named types of local packages which implement the interface,
+// by value or by pointer, sorted by package path and name.
+// Results are cached for each interface, and a copy is returned, which is safe to modify.
+func (v *Universe) Implementations(iface TypeName) ([]Implementation, error) {
+	i, err := v.interfaceOf(iface)
+	if err != nil {
+		return nil, err
+	}
+
+	key := iface.Pkg().Path() + "." + iface.Name()
+
+	v.implementations.mu.Lock()
+	defer v.implementations.mu.Unlock()
+
+	if cached, ok := v.implementations.cache[key]; ok {
+		return slices.Clone(cached), nil
+	}
+
+	list := make([]Implementation, 0)
+
+	for pkgPath := range v.LocalPkgPaths() {
+		p := v.Package(pkgPath)
+		if p == nil {
+			continue
+		}
+
+		for _, tn := range p.Types() {
+			if impl, ok := implementationOf(tn, i); ok {
+				list = append(list, impl)
+			}
+		}
+	}
+
+	slices.SortFunc(list, func(a, b Implementation) int {
+		return cmp.Or(
+			cmp.Compare(a.Type.Pkg().Path(), b.Type.Pkg().Path()),
+			cmp.Compare(a.Type.Name(), b.Type.Name()),
+		)
+	})
+
+	if v.implementations.cache == nil {
+		v.implementations.cache = map[string][]Implementation{}
+	}
+	v.implementations.cache[key] = list
+
+	return slices.Clone(list), nil
+}
+
+// Implements checks whether named type implements the interface, by value or by pointer
+func (v *Universe) Implements(tn *types.TypeName, iface TypeName) (Implementation, bool, error) {
+	i, err := v.interfaceOf(iface)
+	if err != nil {
+		return Implementation{}, false, err
+	}
+
+	impl, ok := implementationOf(tn, i)
+	return impl, ok, nil
+}
+
+func (v *Universe) interfaceOf(iface TypeName) (*types.Interface, error) {
+	p := v.Package(iface.Pkg().Path())
+	if p == nil {
+		return nil, fmt.Errorf("package of %s is not loaded", iface)
+	}
+
+	tn := p.Type(iface.Name())
+	if tn == nil {
+		return nil, fmt.Errorf("%s is not found", iface)
+	}
+
+	if named, ok := tn.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("generic interface %s is not supported", iface)
+	}
+
+	i, ok := tn.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an interface", iface)
+	}
+
+	return i, nil
+}
+
+func implementationOf(tn *types.TypeName, i *types.Interface) (Implementation, bool) {
+	named, ok := tn.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return Implementation{}, false
+	}
+
+	// skip interfaces
+	if types.IsInterface(named) {
+		return Implementation{}, false
+	}
+
+	if types.Implements(named, i) {
+		return Implementation{Type: tn}, true
+	}
+
+	if types.Implements(types.NewPointer(named), i) {
+		return Implementation{Type: tn, Pointer: true}, true
+	}
+
+	return Implementation{}, false
+}
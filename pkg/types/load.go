@@ -114,6 +114,8 @@ type Universe struct {
 	pkgs          map[string]Package
 	localPkgPaths map[string]bool
 	sumFile       *sumfile.File
+
+	implementations implementationIndex
 }
 
 func (v *Universe) SumFile() *sumfile.File {
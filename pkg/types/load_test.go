@@ -1,7 +1,9 @@
 package types
 
 import (
+	"cmp"
 	"go/types"
+	"slices"
 	"testing"
 
 	testingx "github.com/octohelm/x/testing"
@@ -48,10 +50,23 @@ func TestLoad(t *testing.T) {
 					_, lines := p.Doc(f.Pos())
 					testingx.Expect(t, len(lines), testingx.Be(0))
 				}
+
+				testingx.Expect(t, p.OwnerOf(f), testingx.Be(tpe))
+
+				// map[string]map[string]struct{ ID int }
+				if f.Name() == "Map" {
+					inline := f.Type().(*types.Map).Elem().(*types.Map).Elem().(*types.Struct)
+					testingx.Expect(t, p.OwnerOf(inline.Field(0)), testingx.Be(tpe))
+				}
 			}
 		})
 	})
 
+	t.Run("Vars", func(t *testing.T) {
+		testingx.Expect(t, p.Var("Var"), testingx.NotBeNil[*types.Var]())
+		testingx.Expect(t, p.Vars(), testingx.HaveLen[map[string]*types.Var](1))
+	})
+
 	tpe := p.Type("FakeBool")
 	testingx.Expect(t, p.MethodsOf(tpe.Type().(*types.Named), false), testingx.HaveLen[[]*types.Func](1))
 	testingx.Expect(t, p.MethodsOf(tpe.Type().(*types.Named), true), testingx.HaveLen[[]*types.Func](1))
@@ -85,3 +100,55 @@ func TestLoad(t *testing.T) {
 		}
 	})
 }
+
+func TestUniverseImplementations(t *testing.T) {
+	u, err := Load([]string{
+		"github.com/octohelm/gengo/testdata/a/c",
+	})
+	testingx.Expect(t, err, testingx.BeNil[error]())
+
+	names := func(list []Implementation) []string {
+		s := make([]string, 0, len(list))
+		for _, impl := range list {
+			name := impl.Type.Pkg().Path() + "." + impl.Type.Name()
+			if impl.Pointer {
+				name = "*" + name
+			}
+			s = append(s, name)
+		}
+		return s
+	}
+
+	objects, err := u.Implementations(Ref("github.com/octohelm/gengo/testdata/a/c", "Object"))
+	testingx.Expect(t, err, testingx.BeNil[error]())
+	testingx.Expect(t, names(objects), testingx.Equal([]string{
+		"*github.com/octohelm/gengo/testdata/a/c.KubePkg",
+	}))
+
+	// cached results should not be changed by modifying the returned
+	objects[0] = Implementation{Type: u.Package("github.com/octohelm/gengo/testdata/a/c").Type("FileSize")}
+
+	objects, err = u.Implementations(Ref("github.com/octohelm/gengo/testdata/a/c", "Object"))
+	testingx.Expect(t, err, testingx.BeNil[error]())
+	testingx.Expect(t, names(objects), testingx.Equal([]string{
+		"*github.com/octohelm/gengo/testdata/a/c.KubePkg",
+	}))
+
+	stringers, err := u.Implementations(Ref("fmt", "Stringer"))
+	testingx.Expect(t, err, testingx.BeNil[error]())
+	testingx.Expect(t, slices.Contains(names(stringers), "github.com/octohelm/gengo/testdata/a/c.FileSize"), testingx.BeTrue())
+	testingx.Expect(t, slices.IsSortedFunc(stringers, func(a, b Implementation) int {
+		return cmp.Or(
+			cmp.Compare(a.Type.Pkg().Path(), b.Type.Pkg().Path()),
+			cmp.Compare(a.Type.Name(), b.Type.Name()),
+		)
+	}), testingx.BeTrue())
+
+	impl, ok, err := u.Implements(u.Package("github.com/octohelm/gengo/testdata/a/c").Type("KubePkg"), Ref("github.com/octohelm/gengo/testdata/a/c", "Object"))
+	testingx.Expect(t, err, testingx.BeNil[error]())
+	testingx.Expect(t, ok, testingx.BeTrue())
+	testingx.Expect(t, impl.Pointer, testingx.BeTrue())
+
+	_, err = u.Implementations(Ref("github.com/octohelm/gengo/testdata/a/c", "KubePkg"))
+	testingx.Expect(t, err, testingx.NotBeNil[error]())
+}
@@ -45,12 +45,18 @@ type Package interface {
 	Type(name string) *types.TypeName
 	// Types get all types of package
 	Types() map[string]*types.TypeName
+	// Var get package-level variable by name
+	Var(name string) *types.Var
+	// Vars get all package-level variables of package
+	Vars() map[string]*types.Var
 	// Function get function by name
 	Function(name string) *types.Func
 	// Functions get all signatures of package
 	Functions() map[string]*types.Func
 	// MethodsOf get methods of types.TypeName
 	MethodsOf(n *types.Named, canPtr bool) []*types.Func
+	// OwnerOf get declared type which the struct field belongs to
+	OwnerOf(field *types.Var) *types.TypeName
 	// ResultsOf get possible TypeAndValue of function
 	ResultsOf(tpe *types.Func) (results Results, resultN int)
 	// Position get position of pos
@@ -73,8 +79,10 @@ func newPkg(pkg *packages.Package, u *Universe) Package {
 
 		constants: make(map[string]*types.Const),
 		types:     make(map[string]*types.TypeName),
+		vars:      make(map[string]*types.Var),
 		funcs:     make(map[string]*types.Func),
 		methods:   make(map[*types.Named][]*types.Func),
+		owners:    make(map[*types.Var]*types.TypeName),
 
 		endLineToCommentGroup:         make(map[fileLine]*ast.CommentGroup),
 		endLineToTrailingCommentGroup: make(map[fileLine]*ast.CommentGroup),
@@ -140,6 +148,10 @@ func newPkg(pkg *packages.Package, u *Universe) Package {
 			p.types[x.Name()] = x
 		case *types.Const:
 			p.constants[x.Name()] = x
+		case *types.Var:
+			if x.Parent() == pkg.Types.Scope() {
+				p.vars[x.Name()] = x
+			}
 		}
 	}
 
@@ -187,6 +199,12 @@ func newPkg(pkg *packages.Package, u *Universe) Package {
 			case *ast.TypeSpec:
 				collectCommentGroup(x.Doc, false, x.Pos())
 				collectCommentGroup(x.Comment, true, x.Pos())
+
+				if tn, ok := p.Package.TypesInfo.Defs[x.Name].(*types.TypeName); ok {
+					if s, ok := p.Package.TypesInfo.TypeOf(x.Type).(*types.Struct); ok {
+						p.collectOwners(tn, s)
+					}
+				}
 			case *ast.Field:
 				collectCommentGroup(x.Doc, false, x.Pos())
 				collectCommentGroup(x.Comment, true, x.Pos())
@@ -230,8 +248,10 @@ type pkgInfo struct {
 
 	constants map[string]*types.Const
 	types     map[string]*types.TypeName
+	vars      map[string]*types.Var
 	funcs     map[string]*types.Func
 	methods   map[*types.Named][]*types.Func
+	owners    map[*types.Var]*types.TypeName
 
 	endLineToCommentGroup         map[fileLine]*ast.CommentGroup
 	endLineToTrailingCommentGroup map[fileLine]*ast.CommentGroup
@@ -310,6 +330,14 @@ func (p *pkgInfo) Types() map[string]*types.TypeName {
 	return p.types
 }
 
+func (p *pkgInfo) Var(n string) *types.Var {
+	return p.vars[n]
+}
+
+func (p *pkgInfo) Vars() map[string]*types.Var {
+	return p.vars
+}
+
 func (p *pkgInfo) Function(n string) *types.Func {
 	return p.funcs[n]
 }
@@ -338,6 +366,43 @@ func (p *pkgInfo) MethodsOf(n *types.Named, ptr bool) []*types.Func {
 	return notPtrMethods
 }
 
+func (p *pkgInfo) OwnerOf(field *types.Var) *types.TypeName {
+	return p.owners[field.Origin()]
+}
+
+func (p *pkgInfo) collectOwners(owner *types.TypeName, s *types.Struct) {
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+
+		p.owners[f] = owner
+
+		// inline struct fields belong to the declared type too
+		if sub := inlineStructOf(f.Type()); sub != nil {
+			p.collectOwners(owner, sub)
+		}
+	}
+}
+
+// inlineStructOf unwraps pointer, slice, array and map elem to find the inline struct
+func inlineStructOf(t types.Type) *types.Struct {
+	for {
+		switch x := t.(type) {
+		case *types.Struct:
+			return x
+		case *types.Pointer:
+			t = x.Elem()
+		case *types.Slice:
+			t = x.Elem()
+		case *types.Array:
+			t = x.Elem()
+		case *types.Map:
+			t = x.Elem()
+		default:
+			return nil
+		}
+	}
+}
+
 func (p *pkgInfo) Position(pos token.Pos) token.Position {
 	return p.Package.Fset.Position(pos)
 }
@@ -9,10 +9,14 @@ type B string
 
 // Obj some object
 // [[doc/b.md]]
+// +gengo:fieldnames
 type Obj struct {
 	// name
 	// 姓名
 	Name string
+	// internal only
+	// +gengo:runtimedoc:skip
+	Internal string
 	SubObj
 }
 
@@ -1,5 +1,7 @@
 package b
 
+// V
+// +gengo:record
 func V() int {
 	return 2
 }
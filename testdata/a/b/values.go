@@ -0,0 +1,10 @@
+package b
+
+// +gengo:record
+const (
+	ValueA = "a"
+	ValueB = "b"
+)
+
+// +gengo:record
+var DefaultObj = Obj{}
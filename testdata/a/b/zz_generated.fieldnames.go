@@ -0,0 +1,17 @@
+/*
+Package b GENERATED BY gengo:fieldnames
+DON'T EDIT THIS FILE
+*/
+package b
+
+func (*Obj) FieldNames() []string {
+	return []string{
+		"Name",
+		"Internal",
+		"SubObj",
+	}
+}
+
+func (*Obj) New() any {
+	return new(Obj)
+}
@@ -10,3 +10,5 @@ const (
 	PullNever        PullPolicy = "Never"        // never
 	PullIfNotPresent PullPolicy = "IfNotPresent" // if not preset
 )
+
+var Var = PullAlways